@@ -0,0 +1,212 @@
+// Package validators checks Langfuse attribute values and combinations of
+// attributes while Terraform validates the configuration, so that invalid
+// values fail offline instead of with an API error during apply.
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+const (
+	// MinProjectNameLength and MaxProjectNameLength bound the number of
+	// characters of a project name
+	MinProjectNameLength = 3
+	MaxProjectNameLength = 60
+
+	// MaxNoteLength is the maximum number of characters of an API key note
+	MaxNoteLength = 1000
+
+	// MinRetentionDays is the shortest data retention Langfuse accepts
+	MinRetentionDays = 3
+)
+
+// idPattern matches Langfuse identifiers, which are cuids or UUIDs
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+var _ validator.String = projectNameValidator{}
+var _ validator.String = noteValidator{}
+var _ validator.String = idValidator{}
+var _ validator.String = jsonObjectValidator{}
+var _ validator.Int64 = retentionDaysValidator{}
+
+// ProjectName returns a validator for project names. Names have between 3
+// and 60 characters, do not start or end with whitespace and have no
+// control characters.
+func ProjectName() validator.String {
+	return projectNameValidator{}
+}
+
+type projectNameValidator struct{}
+
+func (v projectNameValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("must have between %d and %d characters, must not start or end with whitespace and must not contain control characters", MinProjectNameLength, MaxProjectNameLength)
+}
+
+func (v projectNameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v projectNameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+
+	if length := utf8.RuneCountInString(value); length < MinProjectNameLength || length > MaxProjectNameLength {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueLengthDiagnostic(
+			req.Path,
+			fmt.Sprintf("must have between %d and %d characters", MinProjectNameLength, MaxProjectNameLength),
+			fmt.Sprintf("%d", length),
+		))
+		return
+	}
+
+	if strings.TrimSpace(value) != value {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueMatchDiagnostic(
+			req.Path,
+			"must not start or end with whitespace",
+			fmt.Sprintf("%q", value),
+		))
+		return
+	}
+
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueMatchDiagnostic(
+			req.Path,
+			"must not contain control characters such as line breaks or tabs",
+			fmt.Sprintf("%q", value),
+		))
+	}
+}
+
+// Note returns a validator for API key notes, which have at most 1000
+// characters
+func Note() validator.String {
+	return noteValidator{}
+}
+
+type noteValidator struct{}
+
+func (v noteValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("must have at most %d characters", MaxNoteLength)
+}
+
+func (v noteValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v noteValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if length := utf8.RuneCountInString(req.ConfigValue.ValueString()); length > MaxNoteLength {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueLengthDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			fmt.Sprintf("%d", length),
+		))
+	}
+}
+
+// ID returns a validator for Langfuse identifiers such as project IDs, which
+// only consist of letters, digits, dashes and underscores. It catches
+// project names passed where an ID is expected.
+func ID() validator.String {
+	return idValidator{}
+}
+
+type idValidator struct{}
+
+func (v idValidator) Description(ctx context.Context) string {
+	return "must be a Langfuse identifier of letters, digits, dashes and underscores"
+}
+
+func (v idValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v idValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); !idPattern.MatchString(value) {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueMatchDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			fmt.Sprintf("%q", value),
+		))
+	}
+}
+
+// RetentionDays returns a validator for data retention in days, which is
+// either 0 to disable retention or at least 3
+func RetentionDays() validator.Int64 {
+	return retentionDaysValidator{}
+}
+
+type retentionDaysValidator struct{}
+
+func (v retentionDaysValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("must be 0 to disable data retention or at least %d", MinRetentionDays)
+}
+
+func (v retentionDaysValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v retentionDaysValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueInt64(); value != 0 && value < MinRetentionDays {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			fmt.Sprintf("%d", value),
+		))
+	}
+}
+
+// JSONObject returns a validator for JSON strings that must hold an object,
+// such as project metadata
+func JSONObject() validator.String {
+	return jsonObjectValidator{}
+}
+
+type jsonObjectValidator struct{}
+
+func (v jsonObjectValidator) Description(ctx context.Context) string {
+	return "must be a JSON object"
+}
+
+func (v jsonObjectValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonObjectValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &object); err != nil || object == nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.ValueString(),
+		))
+	}
+}
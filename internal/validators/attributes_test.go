@@ -0,0 +1,83 @@
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func validateString(v validator.String, value types.String) bool {
+	resp := validator.StringResponse{}
+	v.ValidateString(context.Background(), validator.StringRequest{Path: path.Root("test"), ConfigValue: value}, &resp)
+	return !resp.Diagnostics.HasError()
+}
+
+func TestStringValidators(t *testing.T) {
+	testCases := map[string]struct {
+		validator validator.String
+		valid     []string
+		invalid   []string
+	}{
+		"project name": {
+			validator: ProjectName(),
+			valid:     []string{"api", "checkout-service", "Équipe données", strings.Repeat("x", 60)},
+			invalid:   []string{"", "ab", strings.Repeat("x", 61), " checkout", "checkout ", "check\nout", "check\tout"},
+		},
+		"note": {
+			validator: Note(),
+			valid:     []string{"", "x", "Production API key", strings.Repeat("é", 1000)},
+			invalid:   []string{strings.Repeat("x", 1001)},
+		},
+		"id": {
+			validator: ID(),
+			valid:     []string{"clkv6g5jo0000jz088vzn1ja4", "7a88fb47-b4e2-43b8-a06c-a5ce950dc53a", "project_1"},
+			invalid:   []string{"", "checkout service", "-project", "project/1"},
+		},
+		"json object": {
+			validator: JSONObject(),
+			valid:     []string{`{}`, `{"team":"payments","replicas":3}`},
+			invalid:   []string{``, `[]`, `"text"`, `null`, `{`},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, value := range testCase.valid {
+				if !validateString(testCase.validator, types.StringValue(value)) {
+					t.Errorf("expected %q to be valid", value)
+				}
+			}
+			for _, value := range testCase.invalid {
+				if validateString(testCase.validator, types.StringValue(value)) {
+					t.Errorf("expected %q to be invalid", value)
+				}
+			}
+			if !validateString(testCase.validator, types.StringNull()) || !validateString(testCase.validator, types.StringUnknown()) {
+				t.Error("expected null and unknown values to be valid")
+			}
+		})
+	}
+}
+
+func TestRetentionDays(t *testing.T) {
+	testCases := map[int64]bool{
+		-1: false,
+		0:  true,
+		1:  false,
+		2:  false,
+		3:  true,
+		30: true,
+	}
+
+	for value, valid := range testCases {
+		resp := validator.Int64Response{}
+		RetentionDays().ValidateInt64(context.Background(), validator.Int64Request{Path: path.Root("retention_days"), ConfigValue: types.Int64Value(value)}, &resp)
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("retention %d: expected valid to be %t, got %v", value, valid, resp.Diagnostics)
+		}
+	}
+}
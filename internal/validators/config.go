@@ -0,0 +1,108 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ConfigValidator = alsoRequiresOneOfValidator{}
+var _ resource.ConfigValidator = int64LessThanValidator{}
+
+// AlsoRequiresOneOf returns a resource validator that requires at least one
+// of the other attributes to be configured when the attribute is
+// configured
+func AlsoRequiresOneOf(attribute path.Path, others ...path.Path) resource.ConfigValidator {
+	return alsoRequiresOneOfValidator{attribute: attribute, others: others}
+}
+
+type alsoRequiresOneOfValidator struct {
+	attribute path.Path
+	others    []path.Path
+}
+
+func (v alsoRequiresOneOfValidator) Description(ctx context.Context) string {
+	others := make([]string, len(v.others))
+	for i, other := range v.others {
+		others[i] = other.String()
+	}
+
+	return fmt.Sprintf("%s requires one of %s to be configured", v.attribute, strings.Join(others, ", "))
+}
+
+func (v alsoRequiresOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v alsoRequiresOneOfValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var value attr.Value
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, v.attribute, &value)...)
+
+	if resp.Diagnostics.HasError() || value == nil || value.IsNull() {
+		return
+	}
+
+	for _, other := range v.others {
+		var otherValue attr.Value
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, other, &otherValue)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if otherValue != nil && !otherValue.IsNull() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(validatordiag.InvalidAttributeCombinationDiagnostic(v.attribute, v.Description(ctx)))
+}
+
+// Int64LessThan returns a resource validator that requires the lower
+// attribute to be less than the upper attribute when both are configured
+func Int64LessThan(lower, upper path.Path) resource.ConfigValidator {
+	return int64LessThanValidator{lower: lower, upper: upper}
+}
+
+type int64LessThanValidator struct {
+	lower path.Path
+	upper path.Path
+}
+
+func (v int64LessThanValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("%s must be less than %s", v.lower, v.upper)
+}
+
+func (v int64LessThanValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64LessThanValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var lower, upper types.Int64
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, v.lower, &lower)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, v.upper, &upper)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if lower.IsNull() || lower.IsUnknown() || upper.IsNull() || upper.IsUnknown() {
+		return
+	}
+
+	if lower.ValueInt64() >= upper.ValueInt64() {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeCombinationDiagnostic(
+			v.lower,
+			fmt.Sprintf("%s, got %d and %d", v.Description(ctx), lower.ValueInt64(), upper.ValueInt64()),
+		))
+	}
+}
@@ -0,0 +1,94 @@
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testConfig returns a config with a grace period, a rotation period and
+// keepers, where nil values are null
+func testConfig(grace, rotation interface{}, keepers map[string]tftypes.Value) tfsdk.Config {
+	keepersType := tftypes.Map{ElementType: tftypes.String}
+
+	var keepersValue interface{}
+	if keepers != nil {
+		keepersValue = keepers
+	}
+
+	return tfsdk.Config{
+		Schema: schema.Schema{
+			Attributes: map[string]schema.Attribute{
+				"grace":    schema.Int64Attribute{Optional: true},
+				"rotation": schema.Int64Attribute{Optional: true},
+				"keepers":  schema.MapAttribute{ElementType: types.StringType, Optional: true},
+			},
+		},
+		Raw: tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"grace":    tftypes.Number,
+			"rotation": tftypes.Number,
+			"keepers":  keepersType,
+		}}, map[string]tftypes.Value{
+			"grace":    tftypes.NewValue(tftypes.Number, grace),
+			"rotation": tftypes.NewValue(tftypes.Number, rotation),
+			"keepers":  tftypes.NewValue(keepersType, keepersValue),
+		}),
+	}
+}
+
+func validateConfig(v resource.ConfigValidator, config tfsdk.Config) bool {
+	resp := resource.ValidateConfigResponse{}
+	v.ValidateResource(context.Background(), resource.ValidateConfigRequest{Config: config}, &resp)
+	return !resp.Diagnostics.HasError()
+}
+
+func TestAlsoRequiresOneOf(t *testing.T) {
+	v := AlsoRequiresOneOf(path.Root("grace"), path.Root("rotation"), path.Root("keepers"))
+	keepers := map[string]tftypes.Value{"version": tftypes.NewValue(tftypes.String, "1")}
+
+	testCases := map[string]struct {
+		config tfsdk.Config
+		valid  bool
+	}{
+		"unset":              {testConfig(nil, nil, nil), true},
+		"with rotation":      {testConfig(7, 90, nil), true},
+		"with keepers":       {testConfig(7, nil, keepers), true},
+		"with unknown":       {testConfig(7, tftypes.UnknownValue, nil), true},
+		"without either":     {testConfig(7, nil, nil), false},
+		"others without one": {testConfig(nil, 90, keepers), true},
+	}
+
+	for name, testCase := range testCases {
+		if valid := validateConfig(v, testCase.config); valid != testCase.valid {
+			t.Errorf("%s: expected valid to be %t", name, testCase.valid)
+		}
+	}
+}
+
+func TestInt64LessThan(t *testing.T) {
+	v := Int64LessThan(path.Root("grace"), path.Root("rotation"))
+
+	testCases := map[string]struct {
+		config tfsdk.Config
+		valid  bool
+	}{
+		"less":          {testConfig(7, 90, nil), true},
+		"equal":         {testConfig(90, 90, nil), false},
+		"greater":       {testConfig(100, 90, nil), false},
+		"lower unset":   {testConfig(nil, 90, nil), true},
+		"upper unset":   {testConfig(7, nil, nil), true},
+		"upper unknown": {testConfig(100, tftypes.UnknownValue, nil), true},
+	}
+
+	for name, testCase := range testCases {
+		if valid := validateConfig(v, testCase.config); valid != testCase.valid {
+			t.Errorf("%s: expected valid to be %t", name, testCase.valid)
+		}
+	}
+}
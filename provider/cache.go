@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultCacheTTL is how long list responses are reused within a run
+const defaultCacheTTL = 30 * time.Second
+
+// listCache caches list responses for a short time and deduplicates
+// concurrent requests for the same key, so refreshing many resources
+// does not fetch the same listing once per resource.
+type listCache struct {
+	ttl   time.Duration
+	group singleflight.Group
+
+	mu          sync.Mutex
+	entries     map[string]listCacheEntry
+	generations map[string]uint64
+}
+
+// listCacheEntry holds a cached value and its expiry time
+type listCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newListCache(ttl time.Duration) *listCache {
+	return &listCache{
+		ttl:         ttl,
+		entries:     make(map[string]listCacheEntry),
+		generations: make(map[string]uint64),
+	}
+}
+
+// get returns the cached value for key, calling fetch when it is missing or
+// expired. Concurrent callers for the same key share a single fetch. The
+// fetch runs with a context that is not cancelled along with the caller that
+// started it, so one caller timing out does not fail the others, while each
+// caller still stops waiting when its own ctx is done.
+func (c *listCache) get(ctx context.Context, key string, fetch func(context.Context) (interface{}, error)) (interface{}, error) {
+	if c == nil || c.ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && time.Now().Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return entry.value, nil
+	}
+	generation := c.generations[key]
+	c.mu.Unlock()
+
+	fetchCtx := context.WithoutCancel(ctx)
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		value, err := fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		// Skip storing the result if the key was invalidated while the
+		// request was in flight, as it may predate the change.
+		if c.generations[key] == generation {
+			c.entries[key] = listCacheEntry{
+				value:     value,
+				expiresAt: time.Now().Add(c.ttl),
+			}
+		}
+		c.mu.Unlock()
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("error making request: %w", ctx.Err())
+	case res := <-result:
+		return res.Val, res.Err
+	}
+}
+
+// invalidate drops the cached values for the given keys
+func (c *listCache) invalidate(keys ...string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+		c.generations[key]++
+		c.group.Forget(key)
+	}
+}
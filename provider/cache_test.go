@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testProjects returns the given number of numbered projects
+func testProjects(count int) []map[string]interface{} {
+	projects := make([]map[string]interface{}, count)
+	for i := range projects {
+		projects[i] = map[string]interface{}{
+			"id":        fmt.Sprintf("project-%d", i),
+			"name":      fmt.Sprintf("Project %d", i),
+			"metadata":  map[string]interface{}{"index": fmt.Sprint(i)},
+			"createdAt": "2024-01-01T00:00:00.000Z",
+			"updatedAt": "2024-01-01T00:00:00.000Z",
+		}
+	}
+
+	return projects
+}
+
+func TestListProjectsCache(t *testing.T) {
+	server := newFakeProjectsServer(t, testProjects(10)...)
+	client := NewClient(server.URL, "sk", "pk")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := client.GetProject(context.Background(), fmt.Sprintf("project-%d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&server.listCalls); got != 1 {
+		t.Errorf("expected 1 list request, got %d", got)
+	}
+
+	if _, err := client.CreateProject(context.Background(), CreateProjectRequest{Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListProjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadInt64(&server.listCalls); got != 2 {
+		t.Errorf("expected create to invalidate the cache, got %d list requests", got)
+	}
+}
+
+func TestListProjectsCacheReturnsCopy(t *testing.T) {
+	server := newFakeProjectsServer(t, testProjects(1)...)
+	client := NewClient(server.URL, "sk", "pk")
+
+	projects, err := client.ListProjects(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects[0].Name = "modified"
+	projects[0].Metadata["index"] = "modified"
+
+	projects, err = client.ListProjects(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if projects[0].Name != "Project 0" {
+		t.Errorf("cached projects were modified by caller: %q", projects[0].Name)
+	}
+	if projects[0].Metadata["index"] != "0" {
+		t.Errorf("cached project metadata was modified by caller: %v", projects[0].Metadata)
+	}
+}
+
+func TestListProjectsCacheCallerTimeout(t *testing.T) {
+	var listCalls int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&listCalls, 1)
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, `{"projects":[{"id":"project-1","name":"test"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+
+	// The first caller gives up before the shared request completes
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var timeoutErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, timeoutErr = client.ListProjects(ctx)
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	if _, err := client.GetProject(context.Background(), "project-1"); err != nil {
+		t.Errorf("expected the waiting caller to succeed, got: %s", err)
+	}
+	wg.Wait()
+
+	if !errors.Is(timeoutErr, context.DeadlineExceeded) {
+		t.Errorf("expected the first caller to time out, got: %v", timeoutErr)
+	}
+	if got := atomic.LoadInt64(&listCalls); got != 1 {
+		t.Errorf("expected 1 list request, got %d", got)
+	}
+}
+
+// benchmarkRefresh looks up every project once, as a refresh of a
+// configuration managing all of them would.
+func benchmarkRefresh(b *testing.B, projectCount, managedCount int, cached bool) {
+	server := newFakeProjectsServer(b, testProjects(projectCount)...)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		client := NewClient(server.URL, "sk", "pk")
+		if !cached {
+			client.cache = nil
+		}
+
+		for i := 0; i < managedCount; i++ {
+			if _, err := client.GetProject(context.Background(), fmt.Sprintf("project-%d", i)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(atomic.LoadInt64(&server.listCalls))/float64(b.N), "lists/op")
+}
+
+func BenchmarkRefreshUncached(b *testing.B) {
+	benchmarkRefresh(b, 3000, 300, false)
+}
+
+func BenchmarkRefreshCached(b *testing.B) {
+	benchmarkRefresh(b, 3000, 300, true)
+}
@@ -2,10 +2,14 @@ package provider
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Client wraps the Langfuse API client
@@ -13,17 +17,37 @@ type Client struct {
 	ApiHost   string
 	SecretKey string
 	PublicKey string
-	client    *http.Client
+	// PageSize is the default number of items requested per page by
+	// paginated list calls
+	PageSize int
+	// Defaults holds the provider-level defaults of resource attributes
+	Defaults ResourceDefaults
+	client   *http.Client
+	cache    *listCache
+	headers  map[string]string
+
+	// credentialProcess provides the keys instead of SecretKey and
+	// PublicKey when it is set
+	credentialProcess *credentialProcess
+
+	// projectCredentials holds configured keys of projects, and projects
+	// the clients for project-scoped requests
+	projectCredentials map[string]ProjectCredentials
+	projects           *projectClients
+
+	// requestTimeout bounds each request to the Langfuse API
+	requestTimeout time.Duration
 }
 
 // Project represents a Langfuse project
 type Project struct {
-	ID            string                 `json:"id"`
-	Name          string                 `json:"name"`
-	Metadata      map[string]interface{} `json:"metadata"`
-	RetentionDays *int                   `json:"retentionDays"`
-	CreatedAt     string                 `json:"createdAt"`
-	UpdatedAt     string                 `json:"updatedAt"`
+	ID             string                 `json:"id"`
+	Name           string                 `json:"name"`
+	OrganizationID string                 `json:"organizationId,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	RetentionDays  *int                   `json:"retentionDays"`
+	CreatedAt      string                 `json:"createdAt"`
+	UpdatedAt      string                 `json:"updatedAt"`
 }
 
 // ProjectsResponse represents the response from the projects list endpoint
@@ -38,11 +62,31 @@ type CreateProjectRequest struct {
 	Retention *int                   `json:"retention,omitempty"`
 }
 
-// UpdateProjectRequest represents the request to update a project
+// UpdateProjectRequest represents the request to update a project. All
+// fields are sent, so use NewUpdateProjectRequest to reset omitted values.
 type UpdateProjectRequest struct {
 	Name      string                 `json:"name"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
-	Retention *int                   `json:"retention,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	Retention *int                   `json:"retention"`
+}
+
+// NewUpdateProjectRequest returns a request that sets the project to the
+// given values, resetting nil metadata to empty and nil retention to 0,
+// which disables data retention
+func NewUpdateProjectRequest(name string, metadata map[string]interface{}, retention *int) UpdateProjectRequest {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	if retention == nil {
+		retention = new(int)
+	}
+
+	return UpdateProjectRequest{
+		Name:      name,
+		Metadata:  metadata,
+		Retention: retention,
+	}
 }
 
 // ApiKey represents a Langfuse project API key
@@ -70,58 +114,282 @@ type DeleteApiKeyResponse struct {
 	Success bool `json:"success"`
 }
 
+// ScoreConfig represents a Langfuse score config, which defines the type and
+// range of scores in a project
+type ScoreConfig struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	DataType    string                `json:"dataType"`
+	IsArchived  bool                  `json:"isArchived"`
+	MinValue    *float64              `json:"minValue"`
+	MaxValue    *float64              `json:"maxValue"`
+	Categories  []ScoreConfigCategory `json:"categories"`
+	Description *string               `json:"description"`
+	CreatedAt   string                `json:"createdAt"`
+	UpdatedAt   string                `json:"updatedAt"`
+}
+
+// ScoreConfigCategory represents a category of a categorical score config
+type ScoreConfigCategory struct {
+	Value float64 `json:"value"`
+	Label string  `json:"label"`
+}
+
+// ClientOption configures optional behaviour of a Client
+type ClientOption func(*Client)
+
+// WithTransport sets the transport used for requests to the Langfuse API
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.client.Transport = transport
+	}
+}
+
+// WithHeaders sets extra headers sent with every request, for example
+// credentials required by a proxy in front of the Langfuse API
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		c.headers = make(map[string]string, len(headers))
+		for name, value := range headers {
+			c.headers[name] = value
+		}
+	}
+}
+
+// WithRequestTimeout sets the maximum duration of a single request
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithCredentialProcess authenticates requests with the keys printed by a
+// credential process, which are refreshed before they expire
+func WithCredentialProcess(process *credentialProcess) ClientOption {
+	return func(c *Client) {
+		c.credentialProcess = process
+	}
+}
+
 // NewClient creates a new Langfuse API client
-func NewClient(apiHost, secretKey, publicKey string) *Client {
-	return &Client{
+func NewClient(apiHost, secretKey, publicKey string, opts ...ClientOption) *Client {
+	c := &Client{
 		ApiHost:   apiHost,
 		SecretKey: secretKey,
 		PublicKey: publicKey,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		PageSize:  defaultPageSize,
+		client:    &http.Client{},
+		cache:     newListCache(defaultCacheTTL),
+		projects:  newProjectClients(),
+
+		requestTimeout: defaultRequestTimeout,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// projectsCacheKey is the cache key for the organization project list
+const projectsCacheKey = "projects"
+
+// apiKeysCacheKey returns the cache key for a project's API key list
+func apiKeysCacheKey(projectID string) string {
+	return "apiKeys/" + projectID
 }
 
-// makeRequest performs an HTTP request with authentication
-func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
-	var reqBody *bytes.Buffer
+// defaultRequestTimeout is the default maximum duration of a request
+const defaultRequestTimeout = 30 * time.Second
+
+// makeRequest performs an HTTP request with authentication. The request is
+// bounded by the request timeout and the deadline of ctx. The response body
+// is buffered so it can be logged before being returned to the caller.
+func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
-	} else {
-		reqBody = bytes.NewBuffer(nil)
 	}
 
-	url := c.ApiHost + endpoint
-	req, err := http.NewRequest(method, url, reqBody)
+	publicKey, secretKey, err := c.credentials(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.PublicKey, c.SecretKey)
+	ctx = c.logContext(ctx, secretKey)
+	start := time.Now()
 
-	resp, err := c.client.Do(req)
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "Sending Langfuse API request", map[string]interface{}{
+		"method":   method,
+		"endpoint": endpoint,
+		"headers":  c.redactHeaders(c.requestHeaders()),
+		"body":     redactBody(jsonData),
+	})
+
+	resp, respBody, err := c.doRequest(ctx, method, endpoint, jsonData, publicKey, secretKey)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		tflog.SubsystemDebug(ctx, httpLogSubsystem, "Langfuse API request failed", map[string]interface{}{
+			"method":      method,
+			"endpoint":    endpoint,
+			"duration_ms": time.Since(start).Milliseconds(),
+			"error":       err.Error(),
+		})
+		return nil, err
 	}
 
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Langfuse API request completed", map[string]interface{}{
+		"method":      method,
+		"endpoint":    endpoint,
+		"status":      resp.StatusCode,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "Received Langfuse API response", map[string]interface{}{
+		"method":   method,
+		"endpoint": endpoint,
+		"status":   resp.StatusCode,
+		"body":     redactBody(respBody),
+	})
+
 	return resp, nil
 }
 
-// ListProjects retrieves all projects for the organization
-func (c *Client) ListProjects() ([]Project, error) {
-	resp, err := c.makeRequest("GET", "/api/public/organizations/projects", nil)
+// doRequest performs the request, bounded by the request timeout, and returns the response with its body buffered
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, jsonData []byte, publicKey, secretKey string) (*http.Response, []byte, error) {
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.ApiHost+endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header = c.requestHeaders()
+	req.SetBasicAuth(publicKey, secretKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	return resp, respBody, nil
+}
+
+// credentials returns the keys requests are currently authenticated with.
+// With a credential process, these replace SecretKey and PublicKey once the
+// keys from Configure time have been refreshed.
+func (c *Client) credentials(ctx context.Context) (publicKey, secretKey string, err error) {
+	if c.credentialProcess == nil {
+		return c.PublicKey, c.SecretKey, nil
+	}
+
+	credentials, err := c.credentialProcess.Retrieve(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("error refreshing credentials: %w", err)
+	}
+
+	return credentials.PublicKey, credentials.SecretKey, nil
+}
+
+// requestHeaders returns the headers sent with every request, apart from
+// authentication
+func (c *Client) requestHeaders() http.Header {
+	headers := make(http.Header)
+	for name, value := range c.headers {
+		headers.Set(name, value)
+	}
+	headers.Set("Content-Type", "application/json")
+
+	return headers
+}
+
+// ListProjects retrieves all projects for the organization. Responses are
+// cached for a short time and shared between concurrent callers.
+func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+	cached, err := c.cachedProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]Project, len(cached))
+	for i, project := range cached {
+		projects[i] = copyProject(project)
+	}
+
+	return projects, nil
+}
+
+// cachedProjects returns the cached project list, which callers must not
+// modify
+func (c *Client) cachedProjects(ctx context.Context) ([]Project, error) {
+	value, err := c.cache.get(ctx, projectsCacheKey, func(ctx context.Context) (interface{}, error) {
+		return c.fetchProjects(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]Project), nil
+}
+
+// copyProject returns a deep copy of a cached project, so callers can modify
+// it, including its metadata, without changing the cache
+func copyProject(project Project) Project {
+	if project.Metadata != nil {
+		project.Metadata = copyJSONValue(project.Metadata).(map[string]interface{})
+	}
+	if project.RetentionDays != nil {
+		retentionDays := *project.RetentionDays
+		project.RetentionDays = &retentionDays
+	}
+
+	return project
+}
+
+// copyJSONValue returns a deep copy of a decoded JSON value
+func copyJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = copyJSONValue(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = copyJSONValue(child)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// fetchProjects retrieves all projects for the organization from the API
+func (c *Client) fetchProjects(ctx context.Context) ([]Project, error) {
+	resp, err := c.makeRequest(ctx, "GET", "/api/public/organizations/projects", nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		return nil, newAPIError(resp)
 	}
 
 	var projectsResp ProjectsResponse
@@ -133,17 +401,19 @@ func (c *Client) ListProjects() ([]Project, error) {
 }
 
 // CreateProject creates a new project
-func (c *Client) CreateProject(req CreateProjectRequest) (*Project, error) {
-	resp, err := c.makeRequest("POST", "/api/public/projects", req)
+func (c *Client) CreateProject(ctx context.Context, req CreateProjectRequest) (*Project, error) {
+	resp, err := c.makeRequest(ctx, "POST", "/api/public/projects", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		return nil, newAPIError(resp)
 	}
 
+	c.cache.invalidate(projectsCacheKey)
+
 	var project Project
 	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
@@ -153,34 +423,55 @@ func (c *Client) CreateProject(req CreateProjectRequest) (*Project, error) {
 }
 
 // GetProject retrieves a project by ID (implemented using ListProjects)
-func (c *Client) GetProject(projectID string) (*Project, error) {
-	projects, err := c.ListProjects()
+func (c *Client) GetProject(ctx context.Context, projectID string) (*Project, error) {
+	projects, err := c.cachedProjects(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, project := range projects {
 		if project.ID == projectID {
+			project = copyProject(project)
 			return &project, nil
 		}
 	}
 
-	return nil, fmt.Errorf("project with ID %s not found", projectID)
+	return nil, fmt.Errorf("project with ID %s %w", projectID, ErrNotFound)
+}
+
+// FindProjectsByName returns all projects whose name matches exactly.
+// Langfuse allows several projects to share a name.
+func (c *Client) FindProjectsByName(ctx context.Context, name string) ([]Project, error) {
+	projects, err := c.cachedProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Project
+	for _, project := range projects {
+		if project.Name == name {
+			matches = append(matches, copyProject(project))
+		}
+	}
+
+	return matches, nil
 }
 
 // UpdateProject updates an existing project
-func (c *Client) UpdateProject(projectID string, req UpdateProjectRequest) (*Project, error) {
+func (c *Client) UpdateProject(ctx context.Context, projectID string, req UpdateProjectRequest) (*Project, error) {
 	endpoint := fmt.Sprintf("/api/public/projects/%s", projectID)
-	resp, err := c.makeRequest("PUT", endpoint, req)
+	resp, err := c.makeRequest(ctx, "PUT", endpoint, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		return nil, newAPIError(resp)
 	}
 
+	c.cache.invalidate(projectsCacheKey)
+
 	var project Project
 	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
@@ -190,32 +481,92 @@ func (c *Client) UpdateProject(projectID string, req UpdateProjectRequest) (*Pro
 }
 
 // DeleteProject deletes a project by ID
-func (c *Client) DeleteProject(projectID string) error {
+func (c *Client) DeleteProject(ctx context.Context, projectID string) error {
 	endpoint := fmt.Sprintf("/api/public/projects/%s", projectID)
-	resp, err := c.makeRequest("DELETE", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "DELETE", endpoint, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		return newAPIError(resp)
 	}
 
+	c.cache.invalidate(projectsCacheKey, apiKeysCacheKey(projectID))
+
 	return nil
 }
 
-// ListApiKeys retrieves all API keys for a project
-func (c *Client) ListApiKeys(projectID string) ([]ApiKey, error) {
+// projectDeletionPollInterval is how often WaitForProjectDeletion checks
+// whether a project is gone
+var projectDeletionPollInterval = 5 * time.Second
+
+// WaitForProjectDeletion waits until a project deleted with DeleteProject
+// no longer appears in the project list, as Langfuse deletes projects
+// asynchronously. It returns an error when ctx is done first.
+func (c *Client) WaitForProjectDeletion(ctx context.Context, projectID string) error {
+	for {
+		projects, err := c.fetchProjects(ctx)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, project := range projects {
+			if project.ID == projectID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.cache.invalidate(projectsCacheKey)
+			return nil
+		}
+
+		tflog.Debug(ctx, "Waiting for project deletion to complete", map[string]interface{}{"id": projectID})
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("project with ID %s was still present when the wait ended: %w", projectID, ctx.Err())
+		case <-time.After(projectDeletionPollInterval):
+		}
+	}
+}
+
+// ListApiKeys retrieves all API keys for a project. Responses are cached
+// for a short time and shared between concurrent callers.
+func (c *Client) ListApiKeys(ctx context.Context, projectID string) ([]ApiKey, error) {
+	value, err := c.cache.get(ctx, apiKeysCacheKey(projectID), func(ctx context.Context) (interface{}, error) {
+		return c.fetchApiKeys(ctx, projectID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the keys and their notes, so callers cannot change the cache
+	apiKeys := append([]ApiKey(nil), value.([]ApiKey)...)
+	for i := range apiKeys {
+		if apiKeys[i].Note != nil {
+			note := *apiKeys[i].Note
+			apiKeys[i].Note = &note
+		}
+	}
+
+	return apiKeys, nil
+}
+
+// fetchApiKeys retrieves all API keys for a project from the API
+func (c *Client) fetchApiKeys(ctx context.Context, projectID string) ([]ApiKey, error) {
 	endpoint := fmt.Sprintf("/api/public/projects/%s/apiKeys", projectID)
-	resp, err := c.makeRequest("GET", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		return nil, newAPIError(resp)
 	}
 
 	var apiKeysResp ApiKeysResponse
@@ -227,18 +578,20 @@ func (c *Client) ListApiKeys(projectID string) ([]ApiKey, error) {
 }
 
 // CreateApiKey creates a new API key for a project
-func (c *Client) CreateApiKey(projectID string, req CreateApiKeyRequest) (*ApiKey, error) {
+func (c *Client) CreateApiKey(ctx context.Context, projectID string, req CreateApiKeyRequest) (*ApiKey, error) {
 	endpoint := fmt.Sprintf("/api/public/projects/%s/apiKeys", projectID)
-	resp, err := c.makeRequest("POST", endpoint, req)
+	resp, err := c.makeRequest(ctx, "POST", endpoint, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		return nil, newAPIError(resp)
 	}
 
+	c.cache.invalidate(apiKeysCacheKey(projectID))
+
 	var apiKey ApiKey
 	if err := json.NewDecoder(resp.Body).Decode(&apiKey); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
@@ -248,8 +601,8 @@ func (c *Client) CreateApiKey(projectID string, req CreateApiKeyRequest) (*ApiKe
 }
 
 // GetApiKey retrieves a specific API key by ID (implemented using ListApiKeys)
-func (c *Client) GetApiKey(projectID, apiKeyID string) (*ApiKey, error) {
-	apiKeys, err := c.ListApiKeys(projectID)
+func (c *Client) GetApiKey(ctx context.Context, projectID, apiKeyID string) (*ApiKey, error) {
+	apiKeys, err := c.ListApiKeys(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -260,22 +613,24 @@ func (c *Client) GetApiKey(projectID, apiKeyID string) (*ApiKey, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("API key with ID %s not found in project %s", apiKeyID, projectID)
+	return nil, fmt.Errorf("API key with ID %s in project %s %w", apiKeyID, projectID, ErrNotFound)
 }
 
 // DeleteApiKey deletes an API key by ID
-func (c *Client) DeleteApiKey(projectID, apiKeyID string) error {
+func (c *Client) DeleteApiKey(ctx context.Context, projectID, apiKeyID string) error {
 	endpoint := fmt.Sprintf("/api/public/projects/%s/apiKeys/%s", projectID, apiKeyID)
-	resp, err := c.makeRequest("DELETE", endpoint, nil)
+	resp, err := c.makeRequest(ctx, "DELETE", endpoint, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		return newAPIError(resp)
 	}
 
+	c.cache.invalidate(apiKeysCacheKey(projectID))
+
 	var deleteResp DeleteApiKeyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&deleteResp); err != nil {
 		return fmt.Errorf("error decoding response: %w", err)
@@ -286,4 +641,10 @@ func (c *Client) DeleteApiKey(projectID, apiKeyID string) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// ListScoreConfigs retrieves all score configs of the project. It requires a
+// client with project-scoped keys, as returned by ProjectClient.
+func (c *Client) ListScoreConfigs(ctx context.Context) ([]ScoreConfig, error) {
+	return NewPaginator[ScoreConfig](c, "/api/public/score-configs", PaginatorOptions{}).All(ctx)
+}
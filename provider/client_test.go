@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"project-1"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk", WithRequestTimeout(20*time.Millisecond))
+	_, err := client.CreateProject(context.Background(), CreateProjectRequest{Name: "test"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected request to time out, got: %v", err)
+	}
+}
+
+func TestWaitForProjectDeletion(t *testing.T) {
+	previous := projectDeletionPollInterval
+	projectDeletionPollInterval = time.Millisecond
+	defer func() { projectDeletionPollInterval = previous }()
+
+	var listCalls int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			listCalls++
+			if listCalls < 3 {
+				fmt.Fprint(w, `{"projects":[{"id":"project-1","name":"deleting"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"projects":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+
+	// Populate the cache so waiting has to bypass it
+	if _, err := client.GetProject(context.Background(), "project-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteProject(context.Background(), "project-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.WaitForProjectDeletion(context.Background(), "project-1"); err != nil {
+		t.Fatal(err)
+	}
+	if listCalls != 3 {
+		t.Errorf("expected 3 list requests, got %d", listCalls)
+	}
+	if _, err := client.GetProject(context.Background(), "project-1"); !IsNotFound(err) {
+		t.Errorf("expected project to be gone, got: %v", err)
+	}
+}
+
+func TestWaitForProjectDeletionTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"projects":[{"id":"project-1","name":"deleting"}]}`)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := NewClient(server.URL, "sk", "pk").WaitForProjectDeletion(ctx, "project-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected wait to end at the deadline, got: %v", err)
+	}
+}
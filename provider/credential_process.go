@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// credentialRefreshWindow is how long before they expire credentials
+	// from a credential process are refreshed, so that requests started
+	// just before the expiry still succeed
+	credentialRefreshWindow = 5 * time.Minute
+
+	// credentialProcessTimeout bounds a single run of a credential process
+	credentialProcessTimeout = time.Minute
+)
+
+// processCredentials is the output of a credential process
+type processCredentials struct {
+	PublicKey string     `json:"public_key"`
+	SecretKey string     `json:"secret_key"`
+	Host      string     `json:"host"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// credentialProcess runs an external command that prints Langfuse
+// credentials as JSON. The credentials are cached until shortly before they
+// expire, or for the lifetime of the provider when they do not expire.
+type credentialProcess struct {
+	command string
+	group   singleflight.Group
+
+	mu          sync.Mutex
+	credentials *processCredentials
+}
+
+func newCredentialProcess(command string) *credentialProcess {
+	return &credentialProcess{command: command}
+}
+
+// Retrieve returns the cached credentials, running the process when there
+// are none or they are about to expire. Concurrent callers share a single
+// run, which does not hold the lock, and each caller stops waiting when its
+// own ctx is done.
+func (p *credentialProcess) Retrieve(ctx context.Context) (processCredentials, error) {
+	p.mu.Lock()
+	if p.credentials != nil && (p.credentials.ExpiresAt == nil || timeNow().Add(credentialRefreshWindow).Before(*p.credentials.ExpiresAt)) {
+		credentials := *p.credentials
+		p.mu.Unlock()
+		return credentials, nil
+	}
+	p.mu.Unlock()
+
+	runCtx := context.WithoutCancel(ctx)
+	result := p.group.DoChan("credentials", func() (interface{}, error) {
+		credentials, err := p.run(runCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		p.mu.Lock()
+		p.credentials = &credentials
+		p.mu.Unlock()
+
+		return credentials, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return processCredentials{}, fmt.Errorf("credential process: %w", ctx.Err())
+	case res := <-result:
+		if res.Err != nil {
+			return processCredentials{}, res.Err
+		}
+		return res.Val.(processCredentials), nil
+	}
+}
+
+// run runs the process through the shell and parses its output
+func (p *credentialProcess) run(ctx context.Context) (processCredentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", p.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return processCredentials{}, fmt.Errorf("credential process failed: %w: %s", err, message)
+		}
+		return processCredentials{}, fmt.Errorf("credential process failed: %w", err)
+	}
+
+	var credentials processCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return processCredentials{}, fmt.Errorf("credential process printed invalid JSON: %w", err)
+	}
+
+	if credentials.PublicKey == "" || credentials.SecretKey == "" {
+		return processCredentials{}, errors.New("credential process must print both public_key and secret_key")
+	}
+
+	if credentials.ExpiresAt != nil && !credentials.ExpiresAt.After(timeNow()) {
+		return processCredentials{}, fmt.Errorf("credential process printed credentials that expired at %s", credentials.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return credentials, nil
+}
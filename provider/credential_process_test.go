@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testCredentialScript prints credentials numbered by how often it ran,
+// with the host and expiry taken from the environment
+const testCredentialScript = `#!/bin/sh
+count=$(cat "$TEST_COUNT_FILE" 2>/dev/null || echo 0)
+count=$((count + 1))
+echo "$count" > "$TEST_COUNT_FILE"
+printf '{"public_key":"pk-lf-%s","secret_key":"sk-lf-%s","host":"%s","expires_at":"%s"}' "$count" "$count" "$TEST_HOST" "$TEST_EXPIRES_AT"
+`
+
+// writeCredentialScript writes the test credential process and returns the
+// command to run it together with the file counting its runs
+func writeCredentialScript(t *testing.T, host string, expiresAt time.Time) (command, countFile string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test credential process is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "credentials.sh")
+	if err := os.WriteFile(script, []byte(testCredentialScript), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	countFile = filepath.Join(dir, "count")
+	t.Setenv("TEST_COUNT_FILE", countFile)
+	t.Setenv("TEST_HOST", host)
+	t.Setenv("TEST_EXPIRES_AT", expiresAt.Format(time.RFC3339))
+
+	return "sh " + script, countFile
+}
+
+// processRuns returns how often the test credential process ran
+func processRuns(t *testing.T, countFile string) string {
+	t.Helper()
+
+	count, err := os.ReadFile(countFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.TrimSpace(string(count))
+}
+
+func TestCredentialProcessRefreshesBeforeExpiry(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	expiresAt := now.Add(time.Hour)
+	command, countFile := writeCredentialScript(t, "https://cloud.langfuse.com", expiresAt)
+	process := newCredentialProcess(command)
+
+	credentials, err := process.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.PublicKey != "pk-lf-1" || credentials.Host != "https://cloud.langfuse.com" || !credentials.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("unexpected credentials: %+v", credentials)
+	}
+
+	// Credentials are cached until shortly before they expire
+	now = expiresAt.Add(-credentialRefreshWindow - time.Second)
+	if credentials, _ := process.Retrieve(context.Background()); credentials.PublicKey != "pk-lf-1" || processRuns(t, countFile) != "1" {
+		t.Errorf("expected cached credentials, got %s after %s runs", credentials.PublicKey, processRuns(t, countFile))
+	}
+
+	now = expiresAt.Add(-credentialRefreshWindow + time.Second)
+	if credentials, _ := process.Retrieve(context.Background()); credentials.PublicKey != "pk-lf-2" {
+		t.Errorf("expected refreshed credentials, got %s", credentials.PublicKey)
+	}
+}
+
+func TestCredentialProcessSharesRuns(t *testing.T) {
+	command, countFile := writeCredentialScript(t, "https://cloud.langfuse.com", time.Now().Add(time.Hour))
+	process := newCredentialProcess("sleep 0.2; " + command)
+
+	// A caller that gives up does not fail the run the others wait for
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 0 {
+				_, errs[i] = process.Retrieve(ctx)
+				return
+			}
+			_, errs[i] = process.Retrieve(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("expected the first caller to time out, got: %v", errs[0])
+	}
+	for _, err := range errs[1:] {
+		if err != nil {
+			t.Errorf("expected the waiting callers to succeed, got: %s", err)
+		}
+	}
+	if runs := processRuns(t, countFile); runs != "1" {
+		t.Errorf("expected the process to run once, got %s runs", runs)
+	}
+}
+
+func TestCredentialProcessRefreshedKeysInUse(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	expiresAt := now.Add(time.Hour)
+	command, _ := writeCredentialScript(t, "https://cloud.langfuse.com", expiresAt)
+	process := newCredentialProcess(command)
+
+	credentials, err := process.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(credentials.Host, credentials.SecretKey, credentials.PublicKey, WithCredentialProcess(process))
+
+	// The keys from Configure time are refreshed
+	now = expiresAt
+	t.Setenv("TEST_EXPIRES_AT", now.Add(time.Hour).Format(time.RFC3339))
+	r := &ProjectApiKeyResource{client: client}
+
+	var diags diag.Diagnostics
+	r.checkSelfDelete(context.Background(), &diags, types.BoolNull(), "destroy the API key resource", types.StringValue("pk-lf-2"))
+	if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Provider Credentials Would Be Deleted" {
+		t.Errorf("expected deleting the refreshed key to fail, got %v", diags)
+	}
+
+	diags = nil
+	r.checkSelfDelete(context.Background(), &diags, types.BoolNull(), "destroy the API key resource", types.StringValue("pk-lf-1"))
+	if diags.HasError() {
+		t.Errorf("expected the expired key to be deletable, got %v", diags)
+	}
+}
+
+func TestCredentialProcessErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands require a POSIX shell")
+	}
+
+	testCases := map[string]struct {
+		command string
+		message string
+	}{
+		"failure":      {`echo "not logged in" >&2; exit 1`, "not logged in"},
+		"invalid JSON": {`echo "pk-lf-1"`, "invalid JSON"},
+		"missing key":  {`echo '{"public_key":"pk-lf-1"}'`, "public_key and secret_key"},
+		"expired":      {`echo '{"public_key":"pk-lf-1","secret_key":"sk-lf-1","expires_at":"2020-01-01T00:00:00Z"}'`, "expired"},
+	}
+
+	for name, testCase := range testCases {
+		_, err := newCredentialProcess(testCase.command).Retrieve(context.Background())
+		if err == nil || !strings.Contains(err.Error(), testCase.message) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, testCase.message, err)
+		}
+	}
+}
+
+func TestProviderConfigureCredentialProcess(t *testing.T) {
+	clearCredentialsEnv(t)
+
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		publicKey, secretKey, _ := r.BasicAuth()
+		authorizations = append(authorizations, publicKey+":"+secretKey)
+		fmt.Fprint(w, `{"projects":[]}`)
+	}))
+	defer server.Close()
+
+	command, _ := writeCredentialScript(t, server.URL, time.Now().Add(time.Hour))
+
+	client, diags := configureProvider(t, map[string]tftypes.Value{
+		"credential_process": tftypes.NewValue(tftypes.String, command),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if client.ApiHost != server.URL || client.PublicKey != "pk-lf-1" {
+		t.Errorf("expected the host and keys of the process, got %s and %s", client.ApiHost, client.PublicKey)
+	}
+
+	// Requests authenticate with the cached credentials
+	for i := 0; i < 2; i++ {
+		if _, err := client.fetchProjects(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(authorizations) != 2 || authorizations[0] != "pk-lf-1:sk-lf-1" || authorizations[1] != "pk-lf-1:sk-lf-1" {
+		t.Errorf("unexpected authorizations: %v", authorizations)
+	}
+
+	// The process is ignored when the environment also sets it but the
+	// configuration sets the keys
+	t.Setenv("LANGFUSE_CREDENTIAL_PROCESS", command)
+	client, diags = configureProvider(t, map[string]tftypes.Value{
+		"api_host":   tftypes.NewValue(tftypes.String, server.URL),
+		"public_key": tftypes.NewValue(tftypes.String, "pk-lf-config"),
+		"secret_key": tftypes.NewValue(tftypes.String, "sk-lf-config"),
+	})
+	if diags.HasError() || client.credentialProcess != nil || client.PublicKey != "pk-lf-config" {
+		t.Errorf("expected the configured keys to be used, got %v", diags)
+	}
+}
+
+func TestProviderConfigureKeyFiles(t *testing.T) {
+	clearCredentialsEnv(t)
+
+	dir := t.TempDir()
+	secretKeyFile := filepath.Join(dir, "secret_key")
+	publicKeyFile := filepath.Join(dir, "public_key")
+	os.WriteFile(secretKeyFile, []byte("sk-lf-file\n"), 0o600)
+	os.WriteFile(publicKeyFile, []byte("pk-lf-file\n"), 0o600)
+
+	t.Setenv("LANGFUSE_API_HOST", "https://cloud.langfuse.com")
+	t.Setenv("LANGFUSE_SECRET_KEY_FILE", secretKeyFile)
+	t.Setenv("LANGFUSE_PUBLIC_KEY", "pk-lf-env")
+
+	// The key file in the configuration takes precedence over the key in
+	// the environment, which takes precedence over a key file named there
+	client, diags := configureProvider(t, map[string]tftypes.Value{
+		"public_key_file": tftypes.NewValue(tftypes.String, publicKeyFile),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if client.SecretKey != "sk-lf-file" || client.PublicKey != "pk-lf-file" {
+		t.Errorf("expected the keys from the files, got %s and %s", client.SecretKey, client.PublicKey)
+	}
+
+	_, diags = configureProvider(t, map[string]tftypes.Value{
+		"secret_key_file": tftypes.NewValue(tftypes.String, filepath.Join(dir, "missing")),
+	})
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Unable to Read Langfuse Secret Key File" {
+		t.Errorf("expected an error for the missing key file, got %v", diags)
+	}
+}
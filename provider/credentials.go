@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile is the profile used when neither the profile attribute nor
+// LANGFUSE_PROFILE is set
+const defaultProfile = "default"
+
+// credentialsProfile holds the credentials of a named profile in the
+// credentials file
+type credentialsProfile struct {
+	Host      string `yaml:"host"`
+	PublicKey string `yaml:"public_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// defaultCredentialsFile returns the path of the credentials file,
+// $XDG_CONFIG_HOME/langfuse/credentials or ~/.config/langfuse/credentials
+func defaultCredentialsFile() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "langfuse", "credentials"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", "langfuse", "credentials"), nil
+}
+
+// loadCredentialsProfile reads a profile from a credentials file. A missing
+// file is only an error when fileRequired is set and a missing profile only
+// when profileRequired is set, as the default file and profile are optional.
+func loadCredentialsProfile(filename, profile string, fileRequired, profileRequired bool) (credentialsProfile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !fileRequired && !profileRequired {
+			return credentialsProfile{}, nil
+		}
+		return credentialsProfile{}, fmt.Errorf("error reading credentials file: %w", err)
+	}
+
+	profiles, err := parseCredentials(data)
+	if err != nil {
+		return credentialsProfile{}, fmt.Errorf("error parsing credentials file %s: %w", filename, err)
+	}
+
+	credentials, ok := profiles[profile]
+	if !ok && profileRequired {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return credentialsProfile{}, fmt.Errorf("profile %q not found in credentials file %s, available profiles: %v", profile, filename, names)
+	}
+
+	return credentials, nil
+}
+
+// parseCredentials parses a credentials file with a section per profile,
+// either in INI format or as YAML. Files whose first setting is a [section]
+// header are read as INI.
+func parseCredentials(data []byte) (map[string]credentialsProfile, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			return parseCredentialsINI(data)
+		}
+		break
+	}
+
+	profiles := make(map[string]credentialsProfile)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// parseCredentialsINI parses credentials in INI format, with key = value
+// settings below a [profile] header
+func parseCredentialsINI(data []byte) (map[string]credentialsProfile, error) {
+	profiles := make(map[string]credentialsProfile)
+
+	var name string
+	var lineNumber int
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name = strings.TrimSpace(line[1 : len(line)-1])
+			profiles[name] = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected a [profile] header or a key = value setting", lineNumber)
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		profile := profiles[name]
+		switch key {
+		case "host":
+			profile.Host = value
+		case "public_key":
+			profile.PublicKey = value
+		case "secret_key":
+			profile.SecretKey = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown setting %q, expected host, public_key or secret_key", lineNumber, key)
+		}
+		profiles[name] = profile
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// readKeyFile reads a key from a file, ignoring surrounding whitespace such
+// as the trailing newline
+func readKeyFile(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("error reading key file: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("key file %s is empty", filename)
+	}
+
+	return key, nil
+}
+
+// resolveKey returns a key from the first source that sets it: the key
+// attribute, the key file attribute, the environment variable, the file
+// named by the environment variable with a _FILE suffix and finally the
+// given key from the credentials profile
+func resolveKey(profileKey string, key, keyFile types.String, envName string) (string, error) {
+	if !key.IsNull() {
+		return key.ValueString(), nil
+	}
+
+	if !keyFile.IsNull() {
+		return readKeyFile(keyFile.ValueString())
+	}
+
+	if v := os.Getenv(envName); v != "" {
+		return v, nil
+	}
+
+	if filename := os.Getenv(envName + "_FILE"); filename != "" {
+		return readKeyFile(filename)
+	}
+
+	return profileKey, nil
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// credentialsCheckTTL is how long a successful credentials check is
+// remembered, so that consecutive plans do not repeat it
+const credentialsCheckTTL = time.Hour
+
+// userCacheDir returns the directory the credentials checks are remembered
+// in, and is replaced in tests
+var userCacheDir = os.UserCacheDir
+
+// credentialsCheckFile returns the file remembering a successful check of
+// the given host and keys. The name is a hash, so the file does not reveal
+// the keys.
+func credentialsCheckFile(apiHost, publicKey, secretKey string) (string, error) {
+	dir, err := userCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(apiHost + "\x00" + publicKey + "\x00" + secretKey))
+	return filepath.Join(dir, "terraform-provider-langfuse", "credentials", hex.EncodeToString(sum[:])), nil
+}
+
+// CheckCredentials makes a cheap authenticated request to verify that the
+// API host is reachable and accepts the keys as an organization key. A
+// successful check is remembered on disk for credentialsCheckTTL.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	publicKey, secretKey, err := c.credentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	filename, err := credentialsCheckFile(c.ApiHost, publicKey, secretKey)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to remember the Langfuse credentials check", map[string]interface{}{"error": err.Error()})
+	} else if content, err := os.ReadFile(filename); err == nil {
+		checkedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(string(content)))
+		if err == nil && timeNow().Sub(checkedAt) < credentialsCheckTTL {
+			tflog.Debug(ctx, "Langfuse credentials were checked recently", map[string]interface{}{"checked_at": checkedAt})
+			return nil
+		}
+	}
+
+	// Listing the projects requires an organization key, and the response
+	// is cached for the resources refreshed right after
+	if _, err := c.ListProjects(ctx); err != nil {
+		return err
+	}
+
+	if filename != "" {
+		err := os.MkdirAll(filepath.Dir(filename), 0o700)
+		if err == nil {
+			err = os.WriteFile(filename, []byte(timeNow().UTC().Format(time.RFC3339)), 0o600)
+		}
+		if err != nil {
+			tflog.Debug(ctx, "Unable to remember the Langfuse credentials check", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	return nil
+}
+
+// addCredentialsCheckError appends a diagnostic for a failed credentials
+// check, against the attribute most likely to be wrong. Keys from a
+// credential process are reported against credential_process.
+func addCredentialsCheckError(diags *diag.Diagnostics, apiHost string, err error, fromProcess bool) {
+	secretKeyPath, publicKeyPath := path.Root("secret_key"), path.Root("public_key")
+	if fromProcess {
+		secretKeyPath, publicKeyPath = path.Root("credential_process"), path.Root("credential_process")
+	}
+
+	var urlErr *url.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case IsUnauthorized(err):
+		diags.AddAttributeError(
+			secretKeyPath,
+			"Invalid Langfuse Credentials",
+			fmt.Sprintf("The Langfuse API at %s rejected the configured public and secret key. "+
+				"Check both keys for typos and that the key was not deleted: %s", apiHost, err),
+		)
+	case IsForbidden(err):
+		diags.AddAttributeError(
+			publicKeyPath,
+			"Langfuse API Key Has the Wrong Scope",
+			fmt.Sprintf("The Langfuse API at %s accepted the keys but does not allow them to manage the organization. "+
+				"The provider requires an organization API key, created in the organization settings, rather than a project API key: %s", apiHost, err),
+		)
+	case errors.As(err, &urlErr):
+		diags.AddAttributeError(
+			path.Root("api_host"),
+			"Unable to Reach Langfuse API Host",
+			fmt.Sprintf("The provider cannot connect to the Langfuse API at %s. "+
+				"Check the host for typos and that it is reachable from this machine, including through any proxy: %s", apiHost, err),
+		)
+	case IsNotFound(err) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr):
+		diags.AddAttributeError(
+			path.Root("api_host"),
+			"Unexpected Langfuse API Host Response",
+			fmt.Sprintf("The host %s does not respond like the Langfuse API. "+
+				"Check that the host is the base URL of Langfuse, without the /api path: %s", apiHost, err),
+		)
+	default:
+		diags.AddError(
+			"Unable to Validate Langfuse Credentials",
+			fmt.Sprintf("The provider cannot validate the Langfuse credentials: %s", err),
+		)
+	}
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// setUserCacheDir points the credentials check cache to a temporary
+// directory
+func setUserCacheDir(t *testing.T) {
+	t.Helper()
+
+	previous := userCacheDir
+	dir := t.TempDir()
+	userCacheDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { userCacheDir = previous })
+}
+
+// validatingConfig returns a provider configuration with the given host and
+// keys that validates the credentials
+func validatingConfig(apiHost, publicKey, secretKey string) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"api_host":             tftypes.NewValue(tftypes.String, apiHost),
+		"public_key":           tftypes.NewValue(tftypes.String, publicKey),
+		"secret_key":           tftypes.NewValue(tftypes.String, secretKey),
+		"validate_credentials": tftypes.NewValue(tftypes.Bool, true),
+	}
+}
+
+func TestProviderConfigureValidateCredentials(t *testing.T) {
+	clearCredentialsEnv(t)
+	setUserCacheDir(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch publicKey, _, _ := r.BasicAuth(); publicKey {
+		case "pk-lf-org", "pk-lf-other":
+			fmt.Fprint(w, `{"projects":[]}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	if _, diags := configureProvider(t, validatingConfig(server.URL, "pk-lf-org", "sk-lf-org")); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// A successful check is remembered until it expires, per host and keys
+	if _, diags := configureProvider(t, validatingConfig(server.URL, "pk-lf-org", "sk-lf-org")); diags.HasError() || requests != 1 {
+		t.Errorf("expected the remembered check to be used, got %v after %d requests", diags, requests)
+	}
+	if _, diags := configureProvider(t, validatingConfig(server.URL, "pk-lf-other", "sk-lf-other")); diags.HasError() || requests != 2 {
+		t.Errorf("expected other keys to be checked, got %v after %d requests", diags, requests)
+	}
+
+	now := time.Now()
+	timeNow = func() time.Time { return now.Add(credentialsCheckTTL) }
+	defer func() { timeNow = time.Now }()
+
+	if _, diags := configureProvider(t, validatingConfig(server.URL, "pk-lf-org", "sk-lf-org")); diags.HasError() || requests != 3 {
+		t.Errorf("expected an expired check to be repeated, got %v after %d requests", diags, requests)
+	}
+
+	// Credentials are only checked when requested
+	config := validatingConfig(server.URL, "pk-lf-typo", "sk-lf-org")
+	config["validate_credentials"] = tftypes.NewValue(tftypes.Bool, nil)
+	if _, diags := configureProvider(t, config); diags.HasError() || requests != 3 {
+		t.Errorf("expected no check, got %v after %d requests", diags, requests)
+	}
+}
+
+func TestProviderConfigureValidateCredentialsErrors(t *testing.T) {
+	clearCredentialsEnv(t)
+	setUserCacheDir(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch publicKey, _, _ := r.BasicAuth(); publicKey {
+		case "pk-lf-project":
+			w.WriteHeader(http.StatusForbidden)
+		case "pk-lf-web":
+			fmt.Fprint(w, `<!DOCTYPE html><html></html>`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	testCases := map[string]struct {
+		config  map[string]tftypes.Value
+		path    path.Path
+		summary string
+	}{
+		"bad credentials": {
+			config:  validatingConfig(server.URL, "pk-lf-typo", "sk-lf-org"),
+			path:    path.Root("secret_key"),
+			summary: "Invalid Langfuse Credentials",
+		},
+		"wrong scope": {
+			config:  validatingConfig(server.URL, "pk-lf-project", "sk-lf-project"),
+			path:    path.Root("public_key"),
+			summary: "Langfuse API Key Has the Wrong Scope",
+		},
+		"unreachable host": {
+			config:  validatingConfig(closed.URL, "pk-lf-org", "sk-lf-org"),
+			path:    path.Root("api_host"),
+			summary: "Unable to Reach Langfuse API Host",
+		},
+		"not the API": {
+			config:  validatingConfig(server.URL, "pk-lf-web", "sk-lf-web"),
+			path:    path.Root("api_host"),
+			summary: "Unexpected Langfuse API Host Response",
+		},
+	}
+
+	for name, testCase := range testCases {
+		_, diags := configureProvider(t, testCase.config)
+		if diags.ErrorsCount() != 1 {
+			t.Errorf("%s: expected one error, got %v", name, diags)
+			continue
+		}
+
+		withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+		if !ok || !withPath.Path().Equal(testCase.path) || withPath.Summary() != testCase.summary {
+			t.Errorf("%s: expected %q on %s, got %v", name, testCase.summary, testCase.path, diags.Errors()[0])
+		}
+	}
+}
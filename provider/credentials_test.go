@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testCredentialsINI = `
+# Langfuse credentials
+[default]
+host = https://cloud.langfuse.com
+public_key = pk-lf-default
+secret_key = sk-lf-default
+
+[eu-staging]
+host = "https://staging.example.com"
+public_key = pk-lf-staging
+secret_key = sk-lf-staging
+`
+
+const testCredentialsYAML = `
+# Langfuse credentials
+default:
+  host: https://cloud.langfuse.com
+  public_key: pk-lf-default
+  secret_key: sk-lf-default
+eu-staging:
+  host: https://staging.example.com
+  public_key: pk-lf-staging
+  secret_key: sk-lf-staging
+`
+
+// writeCredentialsFile writes a credentials file to a temporary directory
+// and returns its path
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+// configureProvider configures the provider with the given attributes, all
+// others being null, and returns the configured client
+func configureProvider(t *testing.T, attributes map[string]tftypes.Value) (*Client, diag.Diagnostics) {
+	t.Helper()
+
+	ctx := context.Background()
+	p := New("test")()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := make(map[string]tftypes.Value, len(configType.AttributeTypes))
+	for name, attrType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+
+	req := provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(configType, values)}}
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, req, &resp)
+
+	client, _ := resp.ResourceData.(*Client)
+	return client, resp.Diagnostics
+}
+
+// clearCredentialsEnv unsets the environment variables that provide
+// credentials, and points the default credentials file to an empty
+// directory
+func clearCredentialsEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range []string{
+		"LANGFUSE_API_HOST", "LANGFUSE_HOST", "LANGFUSE_BASE_URL",
+		"LANGFUSE_SECRET_KEY", "LANGFUSE_PUBLIC_KEY",
+		"LANGFUSE_SECRET_KEY_FILE", "LANGFUSE_PUBLIC_KEY_FILE", "LANGFUSE_CREDENTIAL_PROCESS",
+		"LANGFUSE_PROFILE", "LANGFUSE_CREDENTIALS_FILE",
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+}
+
+func TestParseCredentials(t *testing.T) {
+	for name, content := range map[string]string{"ini": testCredentialsINI, "yaml": testCredentialsYAML} {
+		profiles, err := parseCredentials([]byte(content))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+
+		expected := credentialsProfile{Host: "https://staging.example.com", PublicKey: "pk-lf-staging", SecretKey: "sk-lf-staging"}
+		if len(profiles) != 2 || profiles["eu-staging"] != expected {
+			t.Errorf("%s: unexpected profiles: %+v", name, profiles)
+		}
+	}
+
+	for name, content := range map[string]string{
+		"ini unknown key":      "[default]\nsecret = sk-lf-1\n",
+		"ini setting first":    "secret_key = sk-lf-1\n[default]\n",
+		"ini missing value":    "[default]\nsecret_key\n",
+		"yaml unknown key":     "default:\n  secret: sk-lf-1\n",
+		"yaml not an object":   "- default\n",
+		"yaml invalid profile": "default: sk-lf-1\n",
+	} {
+		if _, err := parseCredentials([]byte(content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	filename := writeCredentialsFile(t, testCredentialsINI)
+	missing := filepath.Join(t.TempDir(), "credentials")
+
+	if profile, err := loadCredentialsProfile(filename, "default", false, true); err != nil || profile.PublicKey != "pk-lf-default" {
+		t.Errorf("unexpected profile %+v and error %v", profile, err)
+	}
+
+	// The default file and profile are optional
+	if profile, err := loadCredentialsProfile(missing, "default", false, false); err != nil || profile != (credentialsProfile{}) {
+		t.Errorf("expected a missing default file to be ignored, got %+v and %v", profile, err)
+	}
+	if profile, err := loadCredentialsProfile(filename, "other", true, false); err != nil || profile != (credentialsProfile{}) {
+		t.Errorf("expected a missing default profile to be ignored, got %+v and %v", profile, err)
+	}
+
+	if _, err := loadCredentialsProfile(missing, "default", true, false); err == nil {
+		t.Error("expected an error for a missing credentials file")
+	}
+	if _, err := loadCredentialsProfile(filename, "other", false, true); err == nil || !strings.Contains(err.Error(), "eu-staging") {
+		t.Errorf("expected an error listing the available profiles, got %v", err)
+	}
+}
+
+func TestProviderConfigureCredentialsProfile(t *testing.T) {
+	str := func(value string) tftypes.Value { return tftypes.NewValue(tftypes.String, value) }
+
+	t.Run("default profile", func(t *testing.T) {
+		clearCredentialsEnv(t)
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if err := os.MkdirAll(filepath.Join(configHome, "langfuse"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(configHome, "langfuse", "credentials"), []byte(testCredentialsYAML), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		client, diags := configureProvider(t, nil)
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if client.ApiHost != "https://cloud.langfuse.com" || client.PublicKey != "pk-lf-default" || client.SecretKey != "sk-lf-default" {
+			t.Errorf("expected the default profile, got %s %s", client.ApiHost, client.PublicKey)
+		}
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		clearCredentialsEnv(t)
+		t.Setenv("LANGFUSE_CREDENTIALS_FILE", writeCredentialsFile(t, testCredentialsINI))
+		t.Setenv("LANGFUSE_PROFILE", "eu-staging")
+		t.Setenv("LANGFUSE_HOST", "https://env.example.com")
+		t.Setenv("LANGFUSE_PUBLIC_KEY", "pk-lf-env")
+
+		client, diags := configureProvider(t, map[string]tftypes.Value{"public_key": str("pk-lf-config")})
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		// Configuration wins over the environment, which wins over the profile
+		if client.PublicKey != "pk-lf-config" || client.ApiHost != "https://env.example.com" || client.SecretKey != "sk-lf-staging" {
+			t.Errorf("unexpected credentials: %s %s", client.ApiHost, client.PublicKey)
+		}
+	})
+
+	t.Run("sdk host names", func(t *testing.T) {
+		clearCredentialsEnv(t)
+		t.Setenv("LANGFUSE_BASE_URL", "https://base-url.example.com")
+		t.Setenv("LANGFUSE_SECRET_KEY", "sk-lf-env")
+		t.Setenv("LANGFUSE_PUBLIC_KEY", "pk-lf-env")
+
+		client, diags := configureProvider(t, nil)
+		if diags.HasError() || client.ApiHost != "https://base-url.example.com" {
+			t.Fatalf("expected LANGFUSE_BASE_URL to set the host, got %v", diags)
+		}
+
+		t.Setenv("LANGFUSE_API_HOST", "https://api-host.example.com")
+		if client, _ := configureProvider(t, nil); client.ApiHost != "https://api-host.example.com" {
+			t.Errorf("expected LANGFUSE_API_HOST to win, got %s", client.ApiHost)
+		}
+	})
+
+	t.Run("missing profile", func(t *testing.T) {
+		clearCredentialsEnv(t)
+
+		_, diags := configureProvider(t, map[string]tftypes.Value{
+			"profile":          str("production"),
+			"credentials_file": str(writeCredentialsFile(t, testCredentialsINI)),
+		})
+		if !diags.HasError() || diags.Errors()[0].Summary() != "Unable to Read Langfuse Credentials Profile" {
+			t.Errorf("expected a profile error, got %v", diags)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cirobaronneto/terraform-provider-langfuse/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ScoreConfigsDataSource{}
+var _ datasource.DataSourceWithConfigure = &ScoreConfigsDataSource{}
+
+func NewScoreConfigsDataSource() datasource.DataSource {
+	return &ScoreConfigsDataSource{}
+}
+
+// ScoreConfigsDataSource defines the data source implementation.
+type ScoreConfigsDataSource struct {
+	client *Client
+}
+
+// ScoreConfigsDataSourceModel describes the data source data model.
+type ScoreConfigsDataSourceModel struct {
+	ProjectID    types.String       `tfsdk:"project_id"`
+	ScoreConfigs []ScoreConfigModel `tfsdk:"score_configs"`
+}
+
+// ScoreConfigModel describes a score config in the data source.
+type ScoreConfigModel struct {
+	ID          types.String               `tfsdk:"id"`
+	Name        types.String               `tfsdk:"name"`
+	DataType    types.String               `tfsdk:"data_type"`
+	IsArchived  types.Bool                 `tfsdk:"is_archived"`
+	MinValue    types.Float64              `tfsdk:"min_value"`
+	MaxValue    types.Float64              `tfsdk:"max_value"`
+	Categories  []ScoreConfigCategoryModel `tfsdk:"categories"`
+	Description types.String               `tfsdk:"description"`
+	CreatedAt   types.String               `tfsdk:"created_at"`
+	UpdatedAt   types.String               `tfsdk:"updated_at"`
+}
+
+// ScoreConfigCategoryModel describes a category of a categorical score config.
+type ScoreConfigCategoryModel struct {
+	Value types.Float64 `tfsdk:"value"`
+	Label types.String  `tfsdk:"label"`
+}
+
+func (d *ScoreConfigsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_score_configs"
+}
+
+func (d *ScoreConfigsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the score configs of a Langfuse project. Reading them requires a project-scoped key, which is taken from the provider's `project_credentials` or created temporarily for the read.",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project identifier",
+				Required:            true,
+				Validators: []validator.String{
+					validators.ID(),
+				},
+			},
+			"score_configs": schema.ListNestedAttribute{
+				MarkdownDescription: "Score configs of the project, including archived ones",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Score config identifier",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the score",
+							Computed:            true,
+						},
+						"data_type": schema.StringAttribute{
+							MarkdownDescription: "Data type of the score: `NUMERIC`, `CATEGORICAL` or `BOOLEAN`",
+							Computed:            true,
+						},
+						"is_archived": schema.BoolAttribute{
+							MarkdownDescription: "Whether the score config is archived",
+							Computed:            true,
+						},
+						"min_value": schema.Float64Attribute{
+							MarkdownDescription: "Minimum value of numeric scores",
+							Computed:            true,
+						},
+						"max_value": schema.Float64Attribute{
+							MarkdownDescription: "Maximum value of numeric scores",
+							Computed:            true,
+						},
+						"categories": schema.ListNestedAttribute{
+							MarkdownDescription: "Categories of categorical scores",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"value": schema.Float64Attribute{
+										MarkdownDescription: "Value of the category",
+										Computed:            true,
+									},
+									"label": schema.StringAttribute{
+										MarkdownDescription: "Label of the category",
+										Computed:            true,
+									},
+								},
+							},
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Description of the score",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "Creation timestamp",
+							Computed:            true,
+						},
+						"updated_at": schema.StringAttribute{
+							MarkdownDescription: "Last update timestamp",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ScoreConfigsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ScoreConfigsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ScoreConfigsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultReadTimeout)
+	defer cancel()
+
+	projectClient, release, err := d.client.ProjectClient(ctx, data.ProjectID.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "get a project API key", err, nil)
+		return
+	}
+	defer release()
+
+	scoreConfigs, err := projectClient.ListScoreConfigs(ctx)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list score configs", err, nil)
+		return
+	}
+
+	data.ScoreConfigs = make([]ScoreConfigModel, len(scoreConfigs))
+	for i, scoreConfig := range scoreConfigs {
+		model := ScoreConfigModel{
+			ID:          types.StringValue(scoreConfig.ID),
+			Name:        types.StringValue(scoreConfig.Name),
+			DataType:    types.StringValue(scoreConfig.DataType),
+			IsArchived:  types.BoolValue(scoreConfig.IsArchived),
+			MinValue:    types.Float64PointerValue(scoreConfig.MinValue),
+			MaxValue:    types.Float64PointerValue(scoreConfig.MaxValue),
+			Description: types.StringPointerValue(scoreConfig.Description),
+			CreatedAt:   types.StringValue(scoreConfig.CreatedAt),
+			UpdatedAt:   types.StringValue(scoreConfig.UpdatedAt),
+		}
+
+		for _, category := range scoreConfig.Categories {
+			model.Categories = append(model.Categories, ScoreConfigCategoryModel{
+				Value: types.Float64Value(category.Value),
+				Label: types.StringValue(category.Label),
+			})
+		}
+
+		data.ScoreConfigs[i] = model
+	}
+
+	tflog.Trace(ctx, "read score configs data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestScoreConfigsDataSourceRead(t *testing.T) {
+	var created, deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/public/score-configs":
+			// Score configs can only be read with the project key
+			if publicKey, _, _ := r.BasicAuth(); publicKey != "pk-lf-temp" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.URL.Query().Get("page") {
+			case "1":
+				fmt.Fprint(w, `{"data":[{"id":"config-1","name":"quality","dataType":"NUMERIC","isArchived":false,"minValue":0,"maxValue":1,"createdAt":"2024-06-01T00:00:00.000Z","updatedAt":"2024-06-01T00:00:00.000Z"}],"meta":{"page":1,"limit":1,"totalItems":2,"totalPages":2}}`)
+			case "2":
+				fmt.Fprint(w, `{"data":[{"id":"config-2","name":"tone","dataType":"CATEGORICAL","isArchived":true,"categories":[{"value":0,"label":"rude"},{"value":1,"label":"polite"}],"description":"Tone of the answer","createdAt":"2024-06-01T00:00:00.000Z","updatedAt":"2024-06-02T00:00:00.000Z"}],"meta":{"page":2,"limit":1,"totalItems":2,"totalPages":2}}`)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		case r.Method == http.MethodPost:
+			created = append(created, "key-temp")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"key-temp","publicKey":"pk-lf-temp","secretKey":"sk-lf-temp","createdAt":"2024-06-01T00:00:00.000Z"}`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, path.Base(r.URL.Path))
+			fmt.Fprint(w, `{"success":true}`)
+		default:
+			fmt.Fprint(w, `{"apiKeys":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL, "sk-lf-org", "pk-lf-org")
+	client.PageSize = 1
+	d := &ScoreConfigsDataSource{client: client}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+	objectType := s.Type().TerraformType(ctx).(tftypes.Object)
+
+	req := datasource.ReadRequest{Config: tfsdk.Config{
+		Schema: s,
+		Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"project_id":    tftypes.NewValue(tftypes.String, "project-1"),
+			"score_configs": tftypes.NewValue(objectType.AttributeTypes["score_configs"], nil),
+		}),
+	}}
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(objectType, nil)}}
+
+	d.Read(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var data ScoreConfigsDataSourceModel
+	if diags := resp.State.Get(ctx, &data); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(data.ScoreConfigs) != 2 {
+		t.Fatalf("expected 2 score configs, got %d", len(data.ScoreConfigs))
+	}
+	numeric, categorical := data.ScoreConfigs[0], data.ScoreConfigs[1]
+	if numeric.Name.ValueString() != "quality" || numeric.MaxValue.ValueFloat64() != 1 || !numeric.Description.IsNull() || numeric.Categories != nil {
+		t.Errorf("unexpected numeric score config: %+v", numeric)
+	}
+	if !categorical.IsArchived.ValueBool() || !categorical.MinValue.IsNull() || len(categorical.Categories) != 2 || categorical.Categories[1].Label.ValueString() != "polite" {
+		t.Errorf("unexpected categorical score config: %+v", categorical)
+	}
+
+	// The temporary key is deleted when the read ends
+	if len(created) != 1 || len(deleted) != 1 || deleted[0] != "key-temp" {
+		t.Errorf("expected the temporary key to be created and deleted, got %v and %v", created, deleted)
+	}
+}
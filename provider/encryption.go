@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// readPGPPublicKey parses a PGP public key given either ASCII armored or as
+// base64 encoded binary, as exported by gpg --export | base64
+func readPGPPublicKey(pgpKey string) (*openpgp.Entity, error) {
+	pgpKey = strings.TrimSpace(pgpKey)
+
+	var entities openpgp.EntityList
+	var err error
+
+	if strings.HasPrefix(pgpKey, "-----BEGIN") {
+		block, armorErr := armor.Decode(strings.NewReader(pgpKey))
+		if armorErr != nil {
+			return nil, fmt.Errorf("error decoding armored PGP key: %w", armorErr)
+		}
+		entities, err = openpgp.ReadKeyRing(block.Body)
+	} else {
+		keyData, decodeErr := base64.StdEncoding.DecodeString(pgpKey)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("PGP key must be ASCII armored or base64 encoded: %w", decodeErr)
+		}
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(keyData))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading PGP key: %w", err)
+	}
+
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("expected exactly one PGP key, found %d", len(entities))
+	}
+
+	return entities[0], nil
+}
+
+// encryptSecret encrypts a secret for the given PGP public key. It returns
+// the base64 encoded encrypted message and the key fingerprint.
+func encryptSecret(pgpKey, secret string) (encrypted, fingerprint string, err error) {
+	entity, err := readPGPPublicKey(pgpKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	var buf bytes.Buffer
+	plaintext, err := openpgp.Encrypt(&buf, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("error encrypting secret: %w", err)
+	}
+
+	if _, err := plaintext.Write([]byte(secret)); err != nil {
+		return "", "", fmt.Errorf("error encrypting secret: %w", err)
+	}
+
+	if err := plaintext.Close(); err != nil {
+		return "", "", fmt.Errorf("error encrypting secret: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), hex.EncodeToString(entity.PrimaryKey.Fingerprint), nil
+}
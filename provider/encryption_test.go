@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// generateTestPGPKey returns a new PGP entity and its armored public key
+func generateTestPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return entity, buf.String()
+}
+
+// decryptTestSecret decrypts a base64 encoded secret with the given entity
+func decryptTestSecret(t *testing.T, entity *openpgp.Entity, encrypted string) string {
+	t.Helper()
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := openpgp.ReadMessage(bytes.NewReader(data), openpgp.EntityList{entity}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := io.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(plaintext)
+}
+
+func TestEncryptSecret(t *testing.T) {
+	entity, armored := generateTestPGPKey(t)
+
+	var binary bytes.Buffer
+	if err := entity.Serialize(&binary); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, pgpKey := range map[string]string{
+		"armored": armored,
+		"base64":  base64.StdEncoding.EncodeToString(binary.Bytes()),
+	} {
+		encrypted, fingerprint, err := encryptSecret(pgpKey, "sk-lf-secret")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if fingerprint != hex.EncodeToString(entity.PrimaryKey.Fingerprint) {
+			t.Errorf("%s: unexpected fingerprint %s", name, fingerprint)
+		}
+		if strings.Contains(encrypted, "sk-lf-secret") {
+			t.Errorf("%s: encrypted secret contains the plaintext", name)
+		}
+		if got := decryptTestSecret(t, entity, encrypted); got != "sk-lf-secret" {
+			t.Errorf("%s: expected decrypted secret, got %q", name, got)
+		}
+	}
+}
+
+func TestEncryptSecretInvalidKey(t *testing.T) {
+	for _, pgpKey := range []string{"", "not a key", base64.StdEncoding.EncodeToString([]byte("not a key"))} {
+		if _, _, err := encryptSecret(pgpKey, "sk-lf-secret"); err == nil {
+			t.Errorf("expected error for PGP key %q", pgpKey)
+		}
+	}
+}
+
+func TestProjectApiKeyResourceRotationEncryptsSecret(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	previousNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = previousNow }()
+
+	entity, armored := generateTestPGPKey(t)
+
+	server := newFakeApiKeyServer(t)
+	r, s := newTestApiKeyResource(t, server.URL)
+
+	state := testApiKeyModel(t, s, now.AddDate(0, 0, -91))
+	state.PGPKey = types.StringValue(armored)
+	state.SecretKey = types.StringNull()
+
+	planned, _ := modifyApiKeyPlan(t, r, s, state, state)
+	if !planned.EncryptedSecretKey.IsUnknown() {
+		t.Fatalf("expected encrypted secret to be unknown after rotation, got %s", planned.EncryptedSecretKey)
+	}
+
+	updated := updateApiKey(t, r, s, state, planned)
+	if !updated.SecretKey.IsNull() {
+		t.Errorf("expected no plaintext secret in state, got %s", updated.SecretKey)
+	}
+	if updated.KeyFingerprint.ValueString() != hex.EncodeToString(entity.PrimaryKey.Fingerprint) {
+		t.Errorf("unexpected fingerprint %s", updated.KeyFingerprint)
+	}
+	if got := decryptTestSecret(t, entity, updated.EncryptedSecretKey.ValueString()); got != "sk-lf-key-new-1" {
+		t.Errorf("expected decrypted secret of the new key, got %q", got)
+	}
+}
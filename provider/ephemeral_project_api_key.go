@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cirobaronneto/terraform-provider-langfuse/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &ProjectApiKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &ProjectApiKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &ProjectApiKeyEphemeralResource{}
+
+// apiKeyPrivateKey is the private data key holding the key to delete on close
+const apiKeyPrivateKey = "api_key"
+
+func NewProjectApiKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &ProjectApiKeyEphemeralResource{}
+}
+
+// ProjectApiKeyEphemeralResource defines the ephemeral resource implementation.
+type ProjectApiKeyEphemeralResource struct {
+	client *Client
+}
+
+// ProjectApiKeyEphemeralResourceModel describes the ephemeral resource data model.
+type ProjectApiKeyEphemeralResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	ProjectID        types.String `tfsdk:"project_id"`
+	Note             types.String `tfsdk:"note"`
+	PublicKey        types.String `tfsdk:"public_key"`
+	SecretKey        types.String `tfsdk:"secret_key"`
+	DisplaySecretKey types.String `tfsdk:"display_secret_key"`
+	CreatedAt        types.String `tfsdk:"created_at"`
+}
+
+// ephemeralApiKey identifies the API key created by Open, for Close
+type ephemeralApiKey struct {
+	ProjectID string `json:"project_id"`
+	ID        string `json:"id"`
+}
+
+func (r *ProjectApiKeyEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_api_key"
+}
+
+func (r *ProjectApiKeyEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a short-lived Langfuse project API key for the duration of a Terraform run. The key is deleted when Terraform closes the ephemeral resource and is never written to plan or state.",
+
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Project identifier",
+				Required:            true,
+				Validators: []validator.String{
+					validators.ID(),
+				},
+			},
+			"note": schema.StringAttribute{
+				MarkdownDescription: "Note for the API key, with at most 1000 characters",
+				Optional:            true,
+				Validators: []validator.String{
+					validators.Note(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "API key identifier",
+				Computed:            true,
+			},
+			"public_key": schema.StringAttribute{
+				MarkdownDescription: "Public key for the API key",
+				Computed:            true,
+			},
+			"secret_key": schema.StringAttribute{
+				MarkdownDescription: "Secret key for the API key",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"display_secret_key": schema.StringAttribute{
+				MarkdownDescription: "Display version of the secret key",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Creation timestamp",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *ProjectApiKeyEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *ProjectApiKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ProjectApiKeyEphemeralResourceModel
+
+	// Read Terraform config data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createReq := CreateApiKeyRequest{}
+	if !data.Note.IsNull() {
+		note := data.Note.ValueString()
+		createReq.Note = &note
+	}
+
+	apiKey, err := r.client.CreateApiKey(ctx, data.ProjectID.ValueString(), createReq)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "create API key", err, apiKeyAPIFields)
+		return
+	}
+
+	// Remember the key so Close can delete it
+	private, err := json.Marshal(ephemeralApiKey{ProjectID: data.ProjectID.ValueString(), ID: apiKey.ID})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Store API Key", err.Error())
+		r.deleteApiKey(ctx, data.ProjectID.ValueString(), apiKey.ID)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, apiKeyPrivateKey, private)...)
+
+	if resp.Diagnostics.HasError() {
+		r.deleteApiKey(ctx, data.ProjectID.ValueString(), apiKey.ID)
+		return
+	}
+
+	data.ID = types.StringValue(apiKey.ID)
+	data.PublicKey = types.StringValue(apiKey.PublicKey)
+	data.SecretKey = types.StringValue(apiKey.SecretKey)
+	data.DisplaySecretKey = types.StringValue(apiKey.DisplaySecretKey)
+	data.CreatedAt = types.StringValue(apiKey.CreatedAt)
+
+	tflog.Trace(ctx, "opened an ephemeral API key")
+
+	// Save data into the ephemeral result
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *ProjectApiKeyEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	private, diags := req.Private.GetKey(ctx, apiKeyPrivateKey)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || private == nil {
+		return
+	}
+
+	var key ephemeralApiKey
+	if err := json.Unmarshal(private, &key); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Delete API Key",
+			fmt.Sprintf("Unable to read the ephemeral API key to delete, got error: %s. The key may need to be deleted manually.", err),
+		)
+		return
+	}
+
+	err := r.client.DeleteApiKey(ctx, key.ProjectID, key.ID)
+	if err != nil && !IsNotFound(err) {
+		addClientError(&resp.Diagnostics, "delete API key", err, apiKeyAPIFields)
+		return
+	}
+
+	tflog.Trace(ctx, "closed an ephemeral API key")
+}
+
+// deleteApiKey removes a key created by Open that could not be handed over
+// to Close
+func (r *ProjectApiKeyEphemeralResource) deleteApiKey(ctx context.Context, projectID, id string) {
+	if err := r.client.DeleteApiKey(ctx, projectID, id); err != nil && !IsNotFound(err) {
+		tflog.Warn(ctx, "Unable to delete ephemeral API key", map[string]interface{}{"id": id, "error": err.Error()})
+	}
+}
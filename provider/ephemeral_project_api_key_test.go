@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// configuredProviderServer returns a provider server configured against the
+// given Langfuse API host
+func configuredProviderServer(t *testing.T, serverURL string) tfprotov6.ProviderServer {
+	t.Helper()
+
+	ctx := context.Background()
+	p := New("test")()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := make(map[string]tftypes.Value, len(configType.AttributeTypes))
+	for name, attrType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["api_host"] = tftypes.NewValue(tftypes.String, serverURL)
+	values["secret_key"] = tftypes.NewValue(tftypes.String, "sk-lf-provider")
+	values["public_key"] = tftypes.NewValue(tftypes.String, "pk-lf-provider")
+
+	config, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := providerserver.NewProtocol6(p)()
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: &config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected configure error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	return server
+}
+
+func TestProjectApiKeyEphemeralResource(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeApiKeyServer(t)
+	server, ok := configuredProviderServer(t, fake.URL).(tfprotov6.EphemeralResourceServer)
+	if !ok {
+		t.Fatal("expected the provider server to serve ephemeral resources")
+	}
+
+	var schemaResp ephemeral.SchemaResponse
+	NewProjectApiKeyEphemeralResource().Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	config, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"project_id":         tftypes.NewValue(tftypes.String, "project-1"),
+		"note":               tftypes.NewValue(tftypes.String, "ci"),
+		"id":                 tftypes.NewValue(tftypes.String, nil),
+		"public_key":         tftypes.NewValue(tftypes.String, nil),
+		"secret_key":         tftypes.NewValue(tftypes.String, nil),
+		"display_secret_key": tftypes.NewValue(tftypes.String, nil),
+		"created_at":         tftypes.NewValue(tftypes.String, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openResp, err := server.OpenEphemeralResource(ctx, &tfprotov6.OpenEphemeralResourceRequest{
+		TypeName: "langfuse_project_api_key",
+		Config:   &config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(openResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected open diagnostics: %s: %s", openResp.Diagnostics[0].Summary, openResp.Diagnostics[0].Detail)
+	}
+
+	result, err := openResp.Result.Unmarshal(objectType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := result.As(&attrs); err != nil {
+		t.Fatal(err)
+	}
+	var secretKey string
+	if err := attrs["secret_key"].As(&secretKey); err != nil || secretKey != "sk-lf-key-new-1" {
+		t.Errorf("expected the secret key of the new key, got %q (%v)", secretKey, err)
+	}
+	if len(fake.deleted) != 0 {
+		t.Fatalf("expected the key to be kept until close, got deletions %v", fake.deleted)
+	}
+
+	closeResp, err := server.CloseEphemeralResource(ctx, &tfprotov6.CloseEphemeralResourceRequest{
+		TypeName: "langfuse_project_api_key",
+		Private:  openResp.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(closeResp.Diagnostics) > 0 {
+		t.Fatalf("unexpected close diagnostics: %s: %s", closeResp.Diagnostics[0].Summary, closeResp.Diagnostics[0].Detail)
+	}
+	if len(fake.deleted) != 1 || fake.deleted[0] != "key-new-1" {
+		t.Errorf("expected the key to be deleted on close, got %v", fake.deleted)
+	}
+}
@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// ErrNotFound is returned when a requested object does not exist
+var ErrNotFound = errors.New("not found")
+
+// maxErrorBodySize limits how much of an error response body is kept
+const maxErrorBodySize = 64 * 1024
+
+// requestIDHeaders lists the response headers that may carry a request ID
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Request-Id", "X-Vercel-Id"}
+
+// APIError represents an unsuccessful response from the Langfuse API
+type APIError struct {
+	StatusCode int
+	Method     string
+	Endpoint   string
+	Message    string
+	Body       string
+	RequestID  string
+	Issues     []APIErrorIssue
+}
+
+// APIErrorIssue represents a single field validation issue reported by the API
+type APIErrorIssue struct {
+	Path    []string
+	Message string
+}
+
+// apiErrorResponse represents the error body returned by the Langfuse API.
+// The error field is either a string or a list of validation issues.
+type apiErrorResponse struct {
+	Message string          `json:"message"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// apiErrorIssueResponse represents a validation issue in an error body
+type apiErrorIssueResponse struct {
+	Path    []interface{} `json:"path"`
+	Message string        `json:"message"`
+}
+
+// newAPIError builds an APIError from a response, consuming its body
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+	}
+
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			apiErr.Endpoint = resp.Request.URL.Path
+		}
+	}
+
+	for _, header := range requestIDHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			apiErr.RequestID = value
+			break
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return apiErr
+	}
+	apiErr.Body = strings.TrimSpace(string(body))
+
+	var errResp apiErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return apiErr
+	}
+
+	apiErr.Message = errResp.Message
+
+	if len(errResp.Error) > 0 {
+		var errString string
+		var issues []apiErrorIssueResponse
+
+		if err := json.Unmarshal(errResp.Error, &errString); err == nil {
+			if apiErr.Message == "" {
+				apiErr.Message = errString
+			} else if errString != "" && errString != apiErr.Message {
+				apiErr.Message = fmt.Sprintf("%s: %s", apiErr.Message, errString)
+			}
+		} else if err := json.Unmarshal(errResp.Error, &issues); err == nil {
+			for _, issue := range issues {
+				issuePath := make([]string, 0, len(issue.Path))
+				for _, step := range issue.Path {
+					issuePath = append(issuePath, fmt.Sprint(step))
+				}
+				apiErr.Issues = append(apiErr.Issues, APIErrorIssue{
+					Path:    issuePath,
+					Message: issue.Message,
+				})
+			}
+		}
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+
+	if e.Method != "" || e.Endpoint != "" {
+		fmt.Fprintf(&sb, "%s %s: ", e.Method, e.Endpoint)
+	}
+
+	fmt.Fprintf(&sb, "API request failed with status %d", e.StatusCode)
+
+	switch {
+	case e.Message != "":
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	case len(e.Issues) == 0 && e.Body != "":
+		fmt.Fprintf(&sb, ": %s", e.Body)
+	}
+
+	for _, issue := range e.Issues {
+		if len(issue.Path) > 0 {
+			fmt.Fprintf(&sb, "; %s: %s", strings.Join(issue.Path, "."), issue.Message)
+		} else {
+			fmt.Fprintf(&sb, "; %s", issue.Message)
+		}
+	}
+
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, " (request id: %s)", e.RequestID)
+	}
+
+	return sb.String()
+}
+
+// IsNotFound reports whether err is an APIError with status 404 or wraps
+// ErrNotFound
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError with status 401
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an APIError with status 403
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is an APIError with status 409
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// addClientError appends a diagnostic for an error returned by the client.
+// Validation issues whose API field appears in fields are reported against
+// the matching attribute so Terraform can point at the offending value.
+func addClientError(diags *diag.Diagnostics, action string, err error, fields map[string]path.Path) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		attributed := false
+		for _, issue := range apiErr.Issues {
+			if len(issue.Path) == 0 {
+				continue
+			}
+			attrPath, ok := fields[issue.Path[0]]
+			if !ok {
+				continue
+			}
+			diags.AddAttributeError(
+				attrPath,
+				"Invalid Attribute Value",
+				fmt.Sprintf("Unable to %s, Langfuse rejected the value: %s", action, issue.Message),
+			)
+			attributed = true
+		}
+		if attributed {
+			return
+		}
+
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			diags.AddError(
+				"Unauthorized",
+				fmt.Sprintf("Unable to %s, the configured Langfuse credentials were rejected: %s", action, apiErr),
+			)
+			return
+		case http.StatusForbidden:
+			diags.AddError(
+				"Forbidden",
+				fmt.Sprintf("Unable to %s, the configured Langfuse credentials are not allowed to perform this operation: %s", action, apiErr),
+			)
+			return
+		}
+	}
+
+	diags.AddError("Client Error", fmt.Sprintf("Unable to %s, got error: %s", action, err))
+}
+
+// addSelfDeleteError reports that a plan would delete the API key the
+// provider itself authenticates with
+func addSelfDeleteError(diags *diag.Diagnostics, publicKey, action string) {
+	diags.AddAttributeError(
+		path.Root("allow_self_delete"),
+		"Provider Credentials Would Be Deleted",
+		fmt.Sprintf("This plan would %s, which deletes the API key %s that the provider is configured with. "+
+			"Terraform would lose access to Langfuse partway through the apply. Configure the provider with a different key first, "+
+			"or set allow_self_delete to true and apply that change before deleting the key.", action, publicKey),
+	)
+}
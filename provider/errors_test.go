@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestAPIErrorFromResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"message":"Invalid request data","error":[{"path":["retention"],"message":"Number must be greater than or equal to 3"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+	retention := 1
+	_, err := client.CreateProject(context.Background(), CreateProjectRequest{Name: "test", Retention: &retention})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected status code: %d", apiErr.StatusCode)
+	}
+	if apiErr.Method != "POST" || apiErr.Endpoint != "/api/public/projects" {
+		t.Errorf("unexpected request: %s %s", apiErr.Method, apiErr.Endpoint)
+	}
+	if apiErr.Message != "Invalid request data" {
+		t.Errorf("unexpected message: %q", apiErr.Message)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("unexpected request id: %q", apiErr.RequestID)
+	}
+	if len(apiErr.Issues) != 1 || apiErr.Issues[0].Path[0] != "retention" {
+		t.Fatalf("unexpected issues: %+v", apiErr.Issues)
+	}
+	if !strings.Contains(err.Error(), "Number must be greater than or equal to 3") {
+		t.Errorf("error message does not include issue: %s", err)
+	}
+
+	var diags diag.Diagnostics
+	addClientError(&diags, "create project", err, projectAPIFields)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected one diagnostic, got %d", diags.ErrorsCount())
+	}
+	withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("retention_days")) {
+		t.Errorf("expected diagnostic on retention_days, got %v", diags.Errors()[0])
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	testCases := map[int]func(error) bool{
+		http.StatusNotFound:     IsNotFound,
+		http.StatusUnauthorized: IsUnauthorized,
+		http.StatusForbidden:    IsForbidden,
+		http.StatusConflict:     IsConflict,
+	}
+
+	for statusCode, check := range testCases {
+		err := fmt.Errorf("wrapped: %w", &APIError{StatusCode: statusCode})
+		if !check(err) {
+			t.Errorf("expected helper to match status %d", statusCode)
+		}
+		if check(&APIError{StatusCode: http.StatusInternalServerError}) {
+			t.Errorf("expected helper for status %d not to match 500", statusCode)
+		}
+	}
+
+	if IsNotFound(fmt.Errorf("plain error")) {
+		t.Error("expected plain error not to match")
+	}
+}
+
+func TestAPIErrorPlainBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "upstream unavailable")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+	_, err := client.ListProjects(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := "GET /api/public/organizations/projects: API request failed with status 502: upstream unavailable"
+	if err.Error() != expected {
+		t.Errorf("unexpected error message:\n got: %s\nwant: %s", err, expected)
+	}
+}
+
+func TestGetProjectNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"projects":[{"id":"other","name":"other"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+	_, err := client.GetProject(context.Background(), "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if err.Error() != "project with ID missing not found" {
+		t.Errorf("unexpected error message: %s", err)
+	}
+}
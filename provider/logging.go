@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// httpLogSubsystem is the tflog subsystem used for Langfuse API traffic.
+// Its level can be set separately with TF_LOG_PROVIDER_LANGFUSE_HTTP.
+const httpLogSubsystem = "http"
+
+// redactedValue replaces sensitive values in logs
+const redactedValue = "***"
+
+// sensitiveBodyKeys lists the JSON keys, in lower case, whose values are
+// redacted from logged request and response bodies. This covers API key
+// secrets as well as LLM connection secrets and extra headers.
+var sensitiveBodyKeys = map[string]bool{
+	"secretkey":    true,
+	"secret_key":   true,
+	"secret":       true,
+	"password":     true,
+	"token":        true,
+	"accesstoken":  true,
+	"apikey":       true,
+	"api_key":      true,
+	"extraheaders": true,
+}
+
+// secretKeyPattern matches Langfuse secret keys in unstructured text
+var secretKeyPattern = regexp.MustCompile(`sk-lf-[A-Za-z0-9-]+`)
+
+// logContext returns a context for logging Langfuse API traffic, with the
+// given secret key of the request, the configured secret key and custom
+// header values masked.
+func (c *Client) logContext(ctx context.Context, secretKey string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, httpLogSubsystem)
+
+	secrets := make([]string, 0, len(c.headers)+2)
+	for _, key := range []string{secretKey, c.SecretKey} {
+		if key != "" {
+			secrets = append(secrets, key)
+		}
+	}
+	for _, value := range c.headers {
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+
+	ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, httpLogSubsystem, secrets...)
+	ctx = tflog.SubsystemMaskMessageStrings(ctx, httpLogSubsystem, secrets...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, httpLogSubsystem, secretKeyPattern)
+
+	return ctx
+}
+
+// redactHeaders returns the request headers for logging, with the
+// Authorization header and custom headers redacted
+func (c *Client) redactHeaders(headers http.Header) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, values := range headers {
+		redacted[name] = strings.Join(values, ", ")
+	}
+
+	redacted["Authorization"] = redactedValue
+	for name := range c.headers {
+		redacted[http.CanonicalHeaderKey(name)] = redactedValue
+	}
+
+	return redacted
+}
+
+// redactBody returns a request or response body for logging, with the
+// values of sensitive JSON keys redacted
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return secretKeyPattern.ReplaceAllString(string(body), redactedValue)
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return redactedValue
+	}
+
+	return string(redacted)
+}
+
+// redactValue walks a decoded JSON value and redacts sensitive keys
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if sensitiveBodyKeys[strings.ToLower(key)] && child != nil {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestMakeRequestLogsRedacted(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_LANGFUSE_HTTP", "TRACE")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"key-1","publicKey":"pk-lf-new","secretKey":"sk-lf-new-secret","displaySecretKey":"sk-lf-...cret"}`)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := NewClient(server.URL, "sk-lf-provider-secret", "pk-lf-provider", WithHeaders(map[string]string{
+		"CF-Access-Client-Secret": "proxy-secret",
+	}))
+	if _, err := client.CreateApiKey(ctx, "project-1", CreateApiKeyRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := output.String()
+	for _, secret := range []string{"sk-lf-new-secret", "sk-lf-provider-secret", "proxy-secret", "Basic "} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain secret %q:\n%s", secret, logs)
+		}
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var completed map[string]interface{}
+	for _, entry := range entries {
+		if entry["@message"] == "Langfuse API request completed" {
+			completed = entry
+		}
+	}
+	if completed == nil {
+		t.Fatalf("expected request completion to be logged:\n%s", logs)
+	}
+	if completed["method"] != "POST" || completed["endpoint"] != "/api/public/projects/project-1/apiKeys" {
+		t.Errorf("unexpected request fields: %v", completed)
+	}
+	if completed["status"] != float64(http.StatusCreated) {
+		t.Errorf("unexpected response fields: %v", completed)
+	}
+	if _, ok := completed["duration_ms"]; !ok {
+		t.Errorf("expected duration to be logged: %v", completed)
+	}
+	if !strings.Contains(logs, "pk-lf-new") {
+		t.Errorf("expected response body to be logged at trace level:\n%s", logs)
+	}
+}
+
+func TestMakeRequestLogsMaskCredentialProcessSecret(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command requires a POSIX shell")
+	}
+	t.Setenv("TF_LOG_PROVIDER_LANGFUSE_HTTP", "TRACE")
+
+	// The server echoes the secret in a field that is not redacted by name
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, secretKey, _ := r.BasicAuth()
+		fmt.Fprintf(w, `{"projects":[],"message":%q}`, secretKey)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	process := newCredentialProcess(`echo '{"public_key":"pk-process","secret_key":"process-secret"}'`)
+	client := NewClient(server.URL, "configured-secret", "pk-configured", WithCredentialProcess(process))
+	if _, err := client.ListProjects(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if logs := output.String(); strings.Contains(logs, "process-secret") {
+		t.Errorf("logs contain the secret of the credential process:\n%s", logs)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	testCases := map[string]string{
+		`{"secretKey":"sk-lf-1","publicKey":"pk-lf-1"}`:                 `{"publicKey":"pk-lf-1","secretKey":"***"}`,
+		`{"data":[{"provider":"openai","secretKey":"sk-openai"}]}`:      `{"data":[{"provider":"openai","secretKey":"***"}]}`,
+		`{"extraHeaders":{"X-Api-Key":"value"},"displaySecretKey":"x"}`: `{"displaySecretKey":"x","extraHeaders":"***"}`,
+		`not json sk-lf-abc-123`:                                        `not json ***`,
+		``:                                                              ``,
+	}
+
+	for body, expected := range testCases {
+		if got := redactBody([]byte(body)); got != expected {
+			t.Errorf("redactBody(%s):\n got: %s\nwant: %s", body, got, expected)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the number of items requested per page when a
+// paginator does not set its own page size
+const defaultPageSize = 50
+
+// PaginationMode selects how a list endpoint is paginated
+type PaginationMode int
+
+const (
+	// PagePagination uses page/limit query parameters and meta.totalPages
+	PagePagination PaginationMode = iota
+	// CursorPagination uses a cursor query parameter and meta.cursor
+	CursorPagination
+)
+
+// PageMeta represents the pagination metadata returned by list endpoints
+type PageMeta struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalItems int    `json:"totalItems"`
+	TotalPages int    `json:"totalPages"`
+	Cursor     string `json:"cursor,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// PageResponse represents a single page returned by a list endpoint
+type PageResponse[T any] struct {
+	Data []T      `json:"data"`
+	Meta PageMeta `json:"meta"`
+}
+
+// PaginatorOptions configures a Paginator
+type PaginatorOptions struct {
+	// Mode selects page or cursor pagination
+	Mode PaginationMode
+	// PageSize is the number of items per page, defaulting to the client page size
+	PageSize int
+	// Query holds additional query parameters sent with every page request
+	Query url.Values
+}
+
+// Paginator lazily fetches the pages of a list endpoint. Pages are only
+// requested when NextPage is called, so callers can stop early without
+// fetching the remainder of the list.
+type Paginator[T any] struct {
+	client   *Client
+	endpoint string
+	options  PaginatorOptions
+
+	page   int
+	cursor string
+	done   bool
+}
+
+// NewPaginator creates a paginator for the given list endpoint
+func NewPaginator[T any](client *Client, endpoint string, options PaginatorOptions) *Paginator[T] {
+	if options.PageSize <= 0 {
+		options.PageSize = client.PageSize
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = defaultPageSize
+	}
+
+	return &Paginator[T]{
+		client:   client,
+		endpoint: endpoint,
+		options:  options,
+		page:     1,
+	}
+}
+
+// HasMore reports whether another page may be available
+func (p *Paginator[T]) HasMore() bool {
+	return !p.done
+}
+
+// NextPage fetches the next page of items
+func (p *Paginator[T]) NextPage(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	// Merge the options into any query the endpoint already has
+	endpoint, err := url.Parse(p.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing endpoint: %w", err)
+	}
+
+	query := endpoint.Query()
+	for key, values := range p.options.Query {
+		query[key] = append([]string(nil), values...)
+	}
+	query.Set("limit", strconv.Itoa(p.options.PageSize))
+
+	switch p.options.Mode {
+	case CursorPagination:
+		if p.cursor != "" {
+			query.Set("cursor", p.cursor)
+		}
+	default:
+		query.Set("page", strconv.Itoa(p.page))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	resp, err := p.client.makeRequest(ctx, "GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var pageResp PageResponse[T]
+	if err := json.NewDecoder(resp.Body).Decode(&pageResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	switch p.options.Mode {
+	case CursorPagination:
+		cursor := pageResp.Meta.NextCursor
+		if cursor == "" {
+			cursor = pageResp.Meta.Cursor
+		}
+		// An API that echoes the request cursor has no further pages
+		p.done = cursor == "" || cursor == p.cursor || len(pageResp.Data) == 0
+		p.cursor = cursor
+	default:
+		p.done = len(pageResp.Data) == 0 || p.page >= pageResp.Meta.TotalPages
+		p.page++
+	}
+
+	return pageResp.Data, nil
+}
+
+// ForEach calls fn for every item until fn returns false or an error, or
+// the list is exhausted
+func (p *Paginator[T]) ForEach(ctx context.Context, fn func(T) (bool, error)) error {
+	for p.HasMore() {
+		items, err := p.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			more, err := fn(item)
+			if err != nil {
+				return err
+			}
+			if !more {
+				p.done = true
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// All fetches every remaining page and returns the collected items
+func (p *Paginator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	err := p.ForEach(ctx, func(item T) (bool, error) {
+		all = append(all, item)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
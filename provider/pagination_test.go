@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+type testItem struct {
+	ID int `json:"id"`
+}
+
+// newPagedServer serves totalItems items using page/limit pagination and
+// records the pages requested.
+func newPagedServer(t *testing.T, totalItems int, requested *[]int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		*requested = append(*requested, page)
+
+		resp := PageResponse[testItem]{
+			Data: []testItem{},
+			Meta: PageMeta{
+				Page:       page,
+				Limit:      limit,
+				TotalItems: totalItems,
+				TotalPages: (totalItems + limit - 1) / limit,
+			},
+		}
+		for i := (page - 1) * limit; i < page*limit && i < totalItems; i++ {
+			resp.Data = append(resp.Data, testItem{ID: i})
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestPaginatorAll(t *testing.T) {
+	var requested []int
+	server := newPagedServer(t, 25, &requested)
+	client := NewClient(server.URL, "sk", "pk")
+
+	items, err := NewPaginator[testItem](client, "/api/public/items", PaginatorOptions{PageSize: 10}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 25 {
+		t.Errorf("expected 25 items, got %d", len(items))
+	}
+	if len(requested) != 3 {
+		t.Errorf("expected 3 page requests, got %v", requested)
+	}
+	for i, item := range items {
+		if item.ID != i {
+			t.Fatalf("unexpected item at %d: %d", i, item.ID)
+		}
+	}
+}
+
+func TestPaginatorEarlyTermination(t *testing.T) {
+	var requested []int
+	server := newPagedServer(t, 100, &requested)
+	client := NewClient(server.URL, "sk", "pk")
+
+	var seen int
+	err := NewPaginator[testItem](client, "/api/public/items", PaginatorOptions{PageSize: 10}).ForEach(context.Background(), func(item testItem) (bool, error) {
+		seen++
+		return item.ID < 14, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seen != 15 {
+		t.Errorf("expected 15 items to be visited, got %d", seen)
+	}
+	if len(requested) != 2 {
+		t.Errorf("expected 2 page requests, got %v", requested)
+	}
+}
+
+func TestPaginatorCursor(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"data":[{"id":1},{"id":2}],"meta":{"cursor":"next"}}`)
+		case "next":
+			fmt.Fprint(w, `{"data":[{"id":3}],"meta":{}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+	paginator := NewPaginator[testItem](client, "/api/public/items", PaginatorOptions{
+		Mode:  CursorPagination,
+		Query: url.Values{"name": []string{"example"}},
+	})
+
+	items, err := paginator.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 3 {
+		t.Errorf("expected 3 items, got %d", len(items))
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(queries))
+	}
+	if queries[0].Get("name") != "example" || queries[0].Get("limit") != strconv.Itoa(defaultPageSize) {
+		t.Errorf("unexpected query: %v", queries[0])
+	}
+	if paginator.HasMore() {
+		t.Error("expected paginator to be exhausted")
+	}
+}
+
+func TestPaginatorCursorEchoed(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 5 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// The request cursor is echoed back instead of an empty next cursor
+		cursor := r.URL.Query().Get("cursor")
+		if cursor == "" {
+			cursor = "first"
+		}
+		fmt.Fprintf(w, `{"data":[{"id":%d}],"meta":{"cursor":%q}}`, requests, cursor)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+	items, err := NewPaginator[testItem](client, "/api/public/items", PaginatorOptions{Mode: CursorPagination}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 || requests != 2 {
+		t.Errorf("expected pagination to stop at the echoed cursor, got %d items after %d requests", len(items), requests)
+	}
+}
+
+func TestPaginatorEndpointWithQuery(t *testing.T) {
+	var requested []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query())
+		fmt.Fprint(w, `{"data":[{"id":1}],"meta":{"page":1,"totalPages":1}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk", "pk")
+	paginator := NewPaginator[testItem](client, "/api/public/items?type=chat", PaginatorOptions{
+		PageSize: 10,
+		Query:    url.Values{"name": []string{"example"}},
+	})
+	if _, err := paginator.All(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requested) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requested))
+	}
+	query := requested[0]
+	if query.Get("type") != "chat" || query.Get("name") != "example" || query.Get("limit") != "10" || query.Get("page") != "1" {
+		t.Errorf("expected the endpoint and option queries to be merged, got %v", query)
+	}
+}
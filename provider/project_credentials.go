@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// temporaryKeyNote is the note of the project keys minted by the provider,
+// by which keys left behind by an interrupted run can be recognized
+const temporaryKeyNote = "Temporary key of the Terraform Langfuse provider"
+
+// staleTemporaryKeyAge is the age after which a temporary key is considered
+// left behind by an interrupted run and deleted when a new key is minted
+const staleTemporaryKeyAge = 24 * time.Hour
+
+// ProjectCredentials holds the keys of a project-scoped API key
+type ProjectCredentials struct {
+	PublicKey string
+	SecretKey string
+}
+
+// projectClients holds the clients authenticated with project-scoped keys
+// that are in use, by project ID. The mutex only guards the map and the user
+// counts, so keys of different projects are minted concurrently.
+type projectClients struct {
+	mu      sync.Mutex
+	clients map[string]*projectClient
+}
+
+// projectClient is a client for a project and the number of operations
+// using it
+type projectClient struct {
+	// mu is held while the client is created, so that concurrent
+	// operations on the project share one temporary key
+	mu     sync.Mutex
+	client *Client
+	// mintedKeyID is the temporary key the client authenticates with, or
+	// empty for configured project credentials
+	mintedKeyID string
+	users       int
+}
+
+func newProjectClients() *projectClients {
+	return &projectClients{clients: make(map[string]*projectClient)}
+}
+
+// WithProjectCredentials sets the keys used for project-scoped requests to
+// the given projects, instead of minting temporary keys
+func WithProjectCredentials(credentials map[string]ProjectCredentials) ClientOption {
+	return func(c *Client) {
+		c.projectCredentials = make(map[string]ProjectCredentials, len(credentials))
+		for projectID, keys := range credentials {
+			c.projectCredentials[projectID] = keys
+		}
+	}
+}
+
+// ProjectClient returns a client for the endpoints that require keys scoped
+// to a project, such as score configs. It uses the configured project
+// credentials of the project, or mints a temporary project key with the
+// organization key of the client. The returned release function must be
+// called when the operation ends. Concurrent operations share a temporary
+// key, which is deleted once the last of them releases it.
+func (c *Client) ProjectClient(ctx context.Context, projectID string) (*Client, func(), error) {
+	c.projects.mu.Lock()
+	entry, ok := c.projects.clients[projectID]
+	if !ok {
+		entry = &projectClient{}
+		c.projects.clients[projectID] = entry
+	}
+	entry.users++
+	c.projects.mu.Unlock()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			c.releaseProjectClient(ctx, projectID, entry)
+		})
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.client == nil {
+		client, mintedKeyID, err := c.newProjectClient(ctx, projectID)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+
+		c.projects.mu.Lock()
+		entry.client, entry.mintedKeyID = client, mintedKeyID
+		c.projects.mu.Unlock()
+	}
+
+	return entry.client, release, nil
+}
+
+// newProjectClient returns a client with the configured credentials of a
+// project, or with a newly minted temporary key and its ID
+func (c *Client) newProjectClient(ctx context.Context, projectID string) (*Client, string, error) {
+	if credentials, ok := c.projectCredentials[projectID]; ok {
+		return c.withKeys(credentials.PublicKey, credentials.SecretKey), "", nil
+	}
+
+	c.deleteStaleTemporaryKeys(ctx, projectID)
+
+	note := temporaryKeyNote
+	apiKey, err := c.CreateApiKey(ctx, projectID, CreateApiKeyRequest{Note: &note})
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating a temporary API key for project %s: %w", projectID, err)
+	}
+
+	return c.withKeys(apiKey.PublicKey, apiKey.SecretKey), apiKey.ID, nil
+}
+
+// releaseProjectClient ends an operation's use of a project client, and
+// deletes its temporary key when no other operation uses it
+func (c *Client) releaseProjectClient(ctx context.Context, projectID string, entry *projectClient) {
+	c.projects.mu.Lock()
+	entry.users--
+	if entry.users > 0 {
+		c.projects.mu.Unlock()
+		return
+	}
+	if c.projects.clients[projectID] == entry {
+		delete(c.projects.clients, projectID)
+	}
+	mintedKeyID := entry.mintedKeyID
+	c.projects.mu.Unlock()
+
+	if mintedKeyID == "" {
+		return
+	}
+
+	// Delete the key even when the operation was cancelled
+	err := c.DeleteApiKey(context.WithoutCancel(ctx), projectID, mintedKeyID)
+	if err != nil && !IsNotFound(err) {
+		tflog.Warn(ctx, "Unable to delete temporary project API key", map[string]interface{}{
+			"project_id": projectID,
+			"id":         mintedKeyID,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// deleteStaleTemporaryKeys deletes the temporary keys of a project that are
+// old enough to have been left behind by an interrupted run. Failures are
+// logged, as they do not affect the operation.
+func (c *Client) deleteStaleTemporaryKeys(ctx context.Context, projectID string) {
+	apiKeys, err := c.ListApiKeys(ctx, projectID)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to list project API keys, skipping temporary key cleanup", map[string]interface{}{"project_id": projectID, "error": err.Error()})
+		return
+	}
+
+	for _, apiKey := range apiKeys {
+		if apiKey.Note == nil || *apiKey.Note != temporaryKeyNote {
+			continue
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, apiKey.CreatedAt)
+		if err != nil || timeNow().Sub(createdAt) < staleTemporaryKeyAge {
+			continue
+		}
+
+		err = c.DeleteApiKey(ctx, projectID, apiKey.ID)
+		if err != nil && !IsNotFound(err) {
+			tflog.Warn(ctx, "Unable to delete stale temporary project API key", map[string]interface{}{"project_id": projectID, "id": apiKey.ID, "error": err.Error()})
+		}
+	}
+}
+
+// withKeys returns a client for the same host and transport that
+// authenticates with the given keys
+func (c *Client) withKeys(publicKey, secretKey string) *Client {
+	return &Client{
+		ApiHost:   c.ApiHost,
+		SecretKey: secretKey,
+		PublicKey: publicKey,
+		PageSize:  c.PageSize,
+		Defaults:  c.Defaults,
+		client:    c.client,
+		cache:     newListCache(defaultCacheTTL),
+		headers:   c.headers,
+		projects:  newProjectClients(),
+
+		requestTimeout: c.requestTimeout,
+	}
+}
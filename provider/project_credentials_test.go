@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestClientProjectClient(t *testing.T) {
+	fake := newFakeApiKeyServer(t)
+	client := NewClient(fake.URL, "sk-lf-org", "pk-lf-org", WithProjectCredentials(map[string]ProjectCredentials{
+		"project-2": {PublicKey: "pk-lf-project-2", SecretKey: "sk-lf-project-2"},
+	}))
+
+	// Concurrent operations share one minted key
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		projectClient, release, err := client.ProjectClient(context.Background(), "project-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, release)
+		if projectClient.PublicKey != "pk-lf-key-new-1" || projectClient.SecretKey != "sk-lf-key-new-1" || projectClient.ApiHost != fake.URL {
+			t.Errorf("unexpected project client: %s %s", projectClient.ApiHost, projectClient.PublicKey)
+		}
+	}
+	if len(fake.created) != 1 {
+		t.Errorf("expected one minted key, got %v", fake.created)
+	}
+
+	// The key is deleted when the last operation ends, without waiting for
+	// the provider to shut down
+	releases[0]()
+	releases[0]()
+	if len(fake.deleted) != 0 {
+		t.Errorf("expected the key to be kept while in use, got %v", fake.deleted)
+	}
+	releases[1]()
+	if len(fake.deleted) != 1 || fake.deleted[0] != "key-new-1" {
+		t.Errorf("expected the minted key to be deleted, got %v", fake.deleted)
+	}
+
+	// A later operation mints a new key
+	_, release, err := client.ProjectClient(context.Background(), "project-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if len(fake.created) != 2 || len(fake.deleted) != 2 || fake.deleted[1] != "key-new-2" {
+		t.Errorf("expected a new key to be minted and deleted, got %v and %v", fake.created, fake.deleted)
+	}
+
+	// Configured project credentials are used as they are and never deleted
+	projectClient, release, err := client.ProjectClient(context.Background(), "project-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if projectClient.PublicKey != "pk-lf-project-2" || len(fake.created) != 2 || len(fake.deleted) != 2 {
+		t.Errorf("expected the configured keys, got %s after minting %v and deleting %v", projectClient.PublicKey, fake.created, fake.deleted)
+	}
+}
+
+func TestClientProjectClientCancelledOperation(t *testing.T) {
+	fake := newFakeApiKeyServer(t)
+	client := NewClient(fake.URL, "sk-lf-org", "pk-lf-org")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, release, err := client.ProjectClient(ctx, "project-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An interrupted operation still deletes its key
+	cancel()
+	release()
+	if len(fake.deleted) != 1 || fake.deleted[0] != "key-new-1" {
+		t.Errorf("expected the minted key to be deleted, got %v", fake.deleted)
+	}
+}
+
+func TestClientProjectClientConcurrentProjects(t *testing.T) {
+	unblock := make(chan struct{})
+	var mu sync.Mutex
+	created := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		projectID := path.Base(path.Dir(r.URL.Path))
+		switch r.Method {
+		case http.MethodPost:
+			// Minting a key of project-1 hangs until the test unblocks it
+			if projectID == "project-1" {
+				<-unblock
+			}
+			mu.Lock()
+			created[projectID]++
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":"key-%[1]s","publicKey":"pk-lf-%[1]s","secretKey":"sk-lf-%[1]s","createdAt":"2024-06-01T00:00:00.000Z"}`, projectID)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"success":true}`)
+		default:
+			fmt.Fprint(w, `{"apiKeys":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk-lf-org", "pk-lf-org")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			projectClient, release, err := client.ProjectClient(context.Background(), "project-1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			if projectClient.PublicKey != "pk-lf-project-1" {
+				t.Errorf("unexpected project client: %s", projectClient.PublicKey)
+			}
+		}()
+	}
+
+	// Wait until all operations on project-1 are waiting for its key
+	for users := 0; users < 3; time.Sleep(time.Millisecond) {
+		client.projects.mu.Lock()
+		if entry, ok := client.projects.clients["project-1"]; ok {
+			users = entry.users
+		}
+		client.projects.mu.Unlock()
+	}
+
+	// Another project does not wait for the key of project-1
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	projectClient, release, err := client.ProjectClient(ctx, "project-2")
+	if err != nil {
+		t.Fatalf("expected project-2 not to wait for project-1, got: %s", err)
+	}
+	release()
+	if projectClient.PublicKey != "pk-lf-project-2" {
+		t.Errorf("unexpected project client: %s", projectClient.PublicKey)
+	}
+
+	close(unblock)
+	wg.Wait()
+
+	// Concurrent operations on project-1 share one key
+	if created["project-1"] != 1 {
+		t.Errorf("expected one key to be minted for project-1, got %d", created["project-1"])
+	}
+}
+
+func TestClientProjectClientDeletesStaleKeys(t *testing.T) {
+	now := time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"apiKeys":[
+				{"id":"key-stale","note":%[1]q,"createdAt":"2024-06-01T00:00:00.000Z"},
+				{"id":"key-recent","note":%[1]q,"createdAt":"2024-06-02T00:00:00.000Z"},
+				{"id":"key-other","note":"CI","createdAt":"2024-01-01T00:00:00.000Z"},
+				{"id":"key-no-note","createdAt":"2024-01-01T00:00:00.000Z"}
+			]}`, temporaryKeyNote)
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"key-new","publicKey":"pk-lf-new","secretKey":"sk-lf-new","createdAt":"2024-06-02T12:00:00.000Z"}`)
+		case http.MethodDelete:
+			deleted = append(deleted, path.Base(r.URL.Path))
+			fmt.Fprint(w, `{"success":true}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk-lf-org", "pk-lf-org")
+	_, release, err := client.ProjectClient(context.Background(), "project-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != "key-stale" {
+		t.Errorf("expected only the stale temporary key to be deleted, got %v", deleted)
+	}
+
+	release()
+	if len(deleted) != 2 || deleted[1] != "key-new" {
+		t.Errorf("expected the minted key to be deleted, got %v", deleted)
+	}
+}
+
+func TestProviderConfigureProjectCredentials(t *testing.T) {
+	clearCredentialsEnv(t)
+
+	credentialsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"public_key": tftypes.String,
+		"secret_key": tftypes.String,
+	}}
+
+	client, diags := configureProvider(t, map[string]tftypes.Value{
+		"api_host":   tftypes.NewValue(tftypes.String, "https://cloud.langfuse.com"),
+		"public_key": tftypes.NewValue(tftypes.String, "pk-lf-org"),
+		"secret_key": tftypes.NewValue(tftypes.String, "sk-lf-org"),
+		"project_credentials": tftypes.NewValue(tftypes.Map{ElementType: credentialsType}, map[string]tftypes.Value{
+			"project-1": tftypes.NewValue(credentialsType, map[string]tftypes.Value{
+				"public_key": tftypes.NewValue(tftypes.String, "pk-lf-project"),
+				"secret_key": tftypes.NewValue(tftypes.String, "sk-lf-project"),
+			}),
+		}),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	expected := ProjectCredentials{PublicKey: "pk-lf-project", SecretKey: "sk-lf-project"}
+	if client.projectCredentials["project-1"] != expected {
+		t.Errorf("unexpected project credentials: %+v", client.projectCredentials)
+	}
+}
+
+func TestProviderConfigureUnknownProjectCredentials(t *testing.T) {
+	clearCredentialsEnv(t)
+
+	credentialsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"public_key": tftypes.String,
+		"secret_key": tftypes.String,
+	}}
+	mapType := tftypes.Map{ElementType: credentialsType}
+
+	testCases := map[string]struct {
+		credentials tftypes.Value
+		expected    []string
+	}{
+		"unknown map": {tftypes.NewValue(mapType, tftypes.UnknownValue), nil},
+		"unknown keys": {tftypes.NewValue(mapType, map[string]tftypes.Value{
+			"project-1": tftypes.NewValue(credentialsType, map[string]tftypes.Value{
+				"public_key": tftypes.NewValue(tftypes.String, "pk-lf-project"),
+				"secret_key": tftypes.NewValue(tftypes.String, "sk-lf-project"),
+			}),
+			"project-2": tftypes.NewValue(credentialsType, map[string]tftypes.Value{
+				"public_key": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"secret_key": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+			"project-3": tftypes.NewValue(credentialsType, tftypes.UnknownValue),
+		}), []string{"project-1"}},
+	}
+
+	for name, testCase := range testCases {
+		client, diags := configureProvider(t, map[string]tftypes.Value{
+			"api_host":            tftypes.NewValue(tftypes.String, "https://cloud.langfuse.com"),
+			"public_key":          tftypes.NewValue(tftypes.String, "pk-lf-org"),
+			"secret_key":          tftypes.NewValue(tftypes.String, "sk-lf-org"),
+			"project_credentials": testCase.credentials,
+		})
+		if diags.HasError() {
+			t.Fatalf("%s: unexpected error: %v", name, diags)
+		}
+
+		// Projects with unknown keys use temporary keys until they are known
+		if len(client.projectCredentials) != len(testCase.expected) {
+			t.Errorf("%s: unexpected project credentials: %+v", name, client.projectCredentials)
+		}
+		for _, projectID := range testCase.expected {
+			if _, ok := client.projectCredentials[projectID]; !ok {
+				t.Errorf("%s: expected credentials of %s, got %+v", name, projectID, client.projectCredentials)
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// encodeMetadata returns project metadata as JSON, with an empty object for
+// projects without metadata
+func encodeMetadata(metadata map[string]interface{}) (jsontypes.Normalized, error) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return jsontypes.NewNormalizedNull(), fmt.Errorf("error encoding project metadata: %w", err)
+	}
+
+	return jsontypes.NewNormalizedValue(string(encoded)), nil
+}
+
+// decodeMetadataJSON parses metadata_json, which must hold a JSON object.
+// Numbers are kept as written so that large integers survive the round trip.
+func decodeMetadataJSON(value string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+
+	var metadata map[string]interface{}
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("metadata_json must be a JSON object: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// mergeMetadata returns the provider default_metadata overridden by the
+// metadata of the project
+func mergeMetadata(defaults map[string]string, metadata map[string]interface{}) map[string]interface{} {
+	if len(defaults) == 0 {
+		return metadata
+	}
+
+	merged := make(map[string]interface{}, len(defaults)+len(metadata))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range metadata {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// stringMetadata returns the string values of metadata as a map value
+func stringMetadata(metadata map[string]interface{}) (types.Map, diag.Diagnostics) {
+	elements := make(map[string]attr.Value)
+	for key, value := range metadata {
+		if strValue, ok := value.(string); ok {
+			elements[key] = types.StringValue(strValue)
+		}
+	}
+
+	return types.MapValue(types.StringType, elements)
+}
+
+// metadataKeys returns the keys the project sets itself, from its metadata
+// map and metadata_json
+func metadataKeys(data ProjectResourceModel) map[string]bool {
+	keys := make(map[string]bool)
+
+	for key := range data.Metadata.Elements() {
+		keys[key] = true
+	}
+
+	if !data.MetadataJSON.IsNull() && !data.MetadataJSON.IsUnknown() {
+		if metadata, err := decodeMetadataJSON(data.MetadataJSON.ValueString()); err == nil {
+			for key := range metadata {
+				keys[key] = true
+			}
+		}
+	}
+
+	return keys
+}
+
+// defaultMetadata returns the provider default_metadata
+func (r *ProjectResource) defaultMetadata() map[string]string {
+	if r.client == nil {
+		return nil
+	}
+
+	return r.client.Defaults.Metadata
+}
+
+// metadataFromModel returns the metadata to send to Langfuse: the metadata
+// map when it is set and metadata_json otherwise, merged over the provider
+// default_metadata
+func (r *ProjectResource) metadataFromModel(data ProjectResourceModel) (map[string]interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if !data.Metadata.IsNull() && !data.Metadata.IsUnknown() {
+		metadata := make(map[string]interface{})
+		for key, value := range data.Metadata.Elements() {
+			if strValue, ok := value.(types.String); ok {
+				metadata[key] = strValue.ValueString()
+			}
+		}
+		return mergeMetadata(r.defaultMetadata(), metadata), diags
+	}
+
+	if data.MetadataJSON.IsNull() || data.MetadataJSON.IsUnknown() {
+		return mergeMetadata(r.defaultMetadata(), nil), diags
+	}
+
+	metadata, err := decodeMetadataJSON(data.MetadataJSON.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("metadata_json"), "Invalid Project Metadata", err.Error())
+		return nil, diags
+	}
+
+	return mergeMetadata(r.defaultMetadata(), metadata), diags
+}
+
+// setMetadata updates the model from the metadata returned by Langfuse.
+// metadata_all holds the string values of all metadata. metadata_json and
+// the metadata map hold the metadata of the project itself, leaving out
+// values that only come from the provider default_metadata. The map only
+// holds string values and is left alone when the project uses metadata_json
+// or has neither metadata nor a metadata map.
+func (r *ProjectResource) setMetadata(data *ProjectResourceModel, metadata map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	all, allDiags := stringMetadata(metadata)
+	diags.Append(allDiags...)
+	data.MetadataAll = all
+
+	ownKeys := metadataKeys(*data)
+	own := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		if defaultValue, ok := r.defaultMetadata()[key]; ok && value == defaultValue && !ownKeys[key] {
+			continue
+		}
+		own[key] = value
+	}
+
+	useMap := !data.Metadata.IsNull() || data.MetadataJSON.IsNull()
+
+	encoded, err := encodeMetadata(own)
+	if err != nil {
+		diags.AddAttributeError(path.Root("metadata_json"), "Invalid Project Metadata", err.Error())
+		return diags
+	}
+	data.MetadataJSON = encoded
+
+	if useMap && (len(own) > 0 || !data.Metadata.IsNull()) {
+		metadataMap, mapDiags := stringMetadata(own)
+		diags.Append(mapDiags...)
+		if !diags.HasError() {
+			data.Metadata = metadataMap
+		}
+	}
+
+	return diags
+}
+
+// planMetadata plans metadata_all from the configured metadata and the
+// provider default_metadata. It also plans metadata_json when it is not
+// configured, as the project then has exactly the metadata map, or no
+// metadata of its own when the map is not configured either.
+func (r *ProjectResource) planMetadata(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var config, state ProjectResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	metadata := make(map[string]interface{})
+	known := true
+
+	switch {
+	case !config.MetadataJSON.IsNull():
+		if config.MetadataJSON.IsUnknown() {
+			known = false
+			break
+		}
+
+		decoded, err := decodeMetadataJSON(config.MetadataJSON.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("metadata_json"), "Invalid Project Metadata", err.Error())
+			return
+		}
+		metadata = decoded
+	case !config.Metadata.IsNull():
+		if config.Metadata.IsUnknown() {
+			known = false
+			break
+		}
+
+		for key, value := range config.Metadata.Elements() {
+			if value.IsUnknown() {
+				known = false
+				break
+			}
+			metadata[key] = value.(types.String).ValueString()
+		}
+	}
+
+	if !known {
+		if config.MetadataJSON.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("metadata_json"), jsontypes.NewNormalizedUnknown())...)
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("metadata_all"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	all, diags := stringMetadata(mergeMetadata(r.defaultMetadata(), metadata))
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("metadata_all"), all)...)
+
+	if !config.MetadataJSON.IsNull() {
+		return
+	}
+
+	planned, err := encodeMetadata(metadata)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("metadata_json"), "Invalid Project Metadata", err.Error())
+		return
+	}
+
+	// The metadata map cannot hold other JSON values, so updating the
+	// project from the map drops them
+	if !config.Metadata.IsNull() && !state.MetadataJSON.IsNull() && !state.MetadataJSON.IsUnknown() {
+		if current, err := decodeMetadataJSON(state.MetadataJSON.ValueString()); err == nil {
+			var dropped []string
+			for key, value := range current {
+				if _, ok := value.(string); !ok {
+					dropped = append(dropped, key)
+				}
+			}
+
+			if len(dropped) > 0 {
+				sort.Strings(dropped)
+				resp.Diagnostics.AddAttributeWarning(
+					path.Root("metadata"),
+					"Project Metadata Will Be Dropped",
+					fmt.Sprintf("The metadata of project %q has keys with values other than strings (%v), which the metadata map cannot hold. "+
+						"Updating the project removes them. Use metadata_json to manage all metadata values.", state.Name.ValueString(), dropped),
+				)
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("metadata_json"), planned)...)
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestDecodeMetadataJSON(t *testing.T) {
+	metadata, err := decodeMetadataJSON(`{"replicas": 12345678901234567890, "enabled": true}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := encodeMetadata(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded.ValueString() != `{"enabled":true,"replicas":12345678901234567890}` {
+		t.Errorf("unexpected round trip: %s", encoded.ValueString())
+	}
+
+	for _, value := range []string{`[]`, `"text"`, `{`} {
+		if _, err := decodeMetadataJSON(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+}
+
+func TestProjectResourceMetadataJSON(t *testing.T) {
+	var requestBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &requestBody)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":"project-1","name":"checkout-service","metadata":%s}`, mustMarshal(t, requestBody["metadata"]))
+	}))
+	defer server.Close()
+
+	r, s := newTestProjectResource(t, server.URL)
+
+	plan := testProjectModel(t, s, false)
+	plan.Metadata = types.MapNull(types.StringType)
+	plan.MetadataJSON = jsontypes.NewNormalizedValue(`{
+		"team": "payments",
+		"replicas": 3,
+		"flags": {"beta": true}
+	}`)
+
+	resp := resource.CreateResponse{State: emptyState(s)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: planFromModel(t, s, &plan)}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	metadata, _ := requestBody["metadata"].(map[string]interface{})
+	if metadata["replicas"] != float64(3) || metadata["flags"].(map[string]interface{})["beta"] != true {
+		t.Errorf("expected non-string metadata to be sent, got %v", requestBody)
+	}
+
+	var state ProjectResourceModel
+	resp.State.Get(context.Background(), &state)
+	if !state.Metadata.IsNull() {
+		t.Errorf("expected the metadata map to stay null, got %s", state.Metadata)
+	}
+
+	equal, diags := state.MetadataJSON.StringSemanticEquals(context.Background(), plan.MetadataJSON)
+	if diags.HasError() || !equal {
+		t.Errorf("expected metadata_json to round trip, got %s", state.MetadataJSON)
+	}
+}
+
+func TestProjectResourcePlanMetadataJSON(t *testing.T) {
+	r, s := newTestProjectResource(t, "http://127.0.0.1:0")
+
+	state := testProjectModel(t, s, false)
+	state.ID = types.StringValue("project-1")
+	state.CreatedAt = types.StringNull()
+	state.UpdatedAt = types.StringNull()
+	state.MetadataJSON = jsontypes.NewNormalizedValue(`{"replicas":3,"team":"payments"}`)
+
+	config := state
+	config.MetadataJSON = jsontypes.NewNormalizedNull()
+	config.Metadata = types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("checkout")})
+
+	plan := config
+	plan.MetadataJSON = state.MetadataJSON
+
+	req := resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: s, Raw: planFromModel(t, s, &config).Raw},
+		State:  stateFromModel(t, s, &state),
+		Plan:   planFromModel(t, s, &plan),
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(context.Background(), req, &resp)
+
+	var planned ProjectResourceModel
+	resp.Plan.Get(context.Background(), &planned)
+	if planned.MetadataJSON.ValueString() != `{"team":"checkout"}` {
+		t.Errorf("expected metadata_json to be planned from the map, got %s", planned.MetadataJSON)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics.Warnings()[0].Summary() != "Project Metadata Will Be Dropped" {
+		t.Errorf("expected a warning about the dropped replicas key, got %v", resp.Diagnostics)
+	}
+}
+
+func TestProjectResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestProjectResource(t, "http://127.0.0.1:0")
+
+	upgrader := r.UpgradeState(ctx)[0]
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx)
+
+	// A version 0 state as written before wait_for_deletion and later
+	// attributes existed
+	raw, err := json.Marshal(map[string]interface{}{
+		"id":             "project-1",
+		"name":           "checkout-service",
+		"metadata":       map[string]string{"team": "payments", "env": "prod"},
+		"retention_days": 30,
+		"created_at":     "2024-01-01T00:00:00.000Z",
+		"updated_at":     "2024-01-01T00:00:00.000Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	priorValue, err := (&tfprotov6.RawState{JSON: raw}).Unmarshal(priorType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.UpgradeStateRequest{State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: priorValue}}
+	resp := resource.UpgradeStateResponse{State: emptyState(schemaResp.Schema)}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var upgraded ProjectResourceModel
+	resp.State.Get(ctx, &upgraded)
+	if upgraded.MetadataJSON.ValueString() != `{"env":"prod","team":"payments"}` {
+		t.Errorf("expected metadata to be migrated, got %s", upgraded.MetadataJSON)
+	}
+	if len(upgraded.Metadata.Elements()) != 2 || upgraded.RetentionDays.ValueInt64() != 30 || upgraded.ID.ValueString() != "project-1" {
+		t.Errorf("expected other attributes to be kept, got %+v", upgraded)
+	}
+}
+
+// mustMarshal encodes a value as JSON
+func mustMarshal(t *testing.T, value interface{}) string {
+	t.Helper()
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(encoded)
+}
+
+func TestProjectResourceDefaultMetadata(t *testing.T) {
+	server := newFakeProjectServer(t)
+	r, s := newTestProjectResource(t, server.URL)
+	r.client.Defaults.Metadata = map[string]string{"owner": "platform", "team": "shared"}
+
+	state := testProjectModel(t, s, false)
+	state.ID = types.StringValue("project-1")
+	state.Metadata = types.MapNull(types.StringType)
+	state.MetadataJSON = jsontypes.NewNormalizedValue(`{}`)
+	state.MetadataAll = types.MapNull(types.StringType)
+	state.CreatedAt = types.StringNull()
+	state.UpdatedAt = types.StringNull()
+
+	config := state
+	config.MetadataJSON = jsontypes.NewNormalizedNull()
+	config.Metadata = types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("payments")})
+
+	plan := config
+	plan.MetadataJSON = state.MetadataJSON
+	modifyReq := resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: s, Raw: planFromModel(t, s, &config).Raw},
+		State:  stateFromModel(t, s, &state),
+		Plan:   planFromModel(t, s, &plan),
+	}
+	modifyResp := resource.ModifyPlanResponse{Plan: modifyReq.Plan}
+	r.ModifyPlan(context.Background(), modifyReq, &modifyResp)
+	if modifyResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected plan error: %v", modifyResp.Diagnostics)
+	}
+
+	updateReq := resource.UpdateRequest{State: stateFromModel(t, s, &state), Plan: modifyResp.Plan}
+	updateResp := resource.UpdateResponse{State: updateReq.State}
+	r.Update(context.Background(), updateReq, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected update error: %v", updateResp.Diagnostics)
+	}
+
+	// The defaults are sent, overridden by the project metadata
+	sent := server.updates[len(server.updates)-1]["metadata"].(map[string]interface{})
+	if sent["owner"] != "platform" || sent["team"] != "payments" {
+		t.Errorf("expected merged metadata to be sent, got %v", sent)
+	}
+
+	var planned, updated ProjectResourceModel
+	modifyResp.Plan.Get(context.Background(), &planned)
+	updateResp.State.Get(context.Background(), &updated)
+
+	expectedAll := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"owner": types.StringValue("platform"),
+		"team":  types.StringValue("payments"),
+	})
+	if !planned.MetadataAll.Equal(expectedAll) || !updated.MetadataAll.Equal(expectedAll) {
+		t.Errorf("expected metadata_all %s, got plan %s and state %s", expectedAll, planned.MetadataAll, updated.MetadataAll)
+	}
+	if !updated.Metadata.Equal(config.Metadata) || !updated.MetadataJSON.Equal(planned.MetadataJSON) {
+		t.Errorf("expected the defaults to stay out of the project metadata, got %s and %s", updated.Metadata, updated.MetadataJSON)
+	}
+
+	// Refreshing does not pull the defaults into the project metadata
+	readReq := resource.ReadRequest{State: updateResp.State}
+	readResp := resource.ReadResponse{State: readReq.State}
+	r.Read(context.Background(), readReq, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected read error: %v", readResp.Diagnostics)
+	}
+
+	var refreshed ProjectResourceModel
+	readResp.State.Get(context.Background(), &refreshed)
+	if !refreshed.Metadata.Equal(config.Metadata) || refreshed.MetadataJSON.ValueString() != `{"team":"payments"}` || !refreshed.MetadataAll.Equal(expectedAll) {
+		t.Errorf("unexpected refreshed metadata: %s, %s, %s", refreshed.Metadata, refreshed.MetadataJSON, refreshed.MetadataAll)
+	}
+}
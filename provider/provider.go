@@ -2,19 +2,38 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure LangfuseProvider satisfies various provider interfaces.
 var _ provider.Provider = &LangfuseProvider{}
+var _ provider.ProviderWithEphemeralResources = &LangfuseProvider{}
+var _ provider.ProviderWithConfigValidators = &LangfuseProvider{}
+
+// Default resource operation timeouts, used when a resource does not set
+// them in its timeouts block.
+const (
+	defaultCreateTimeout = 5 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 5 * time.Minute
+	defaultDeleteTimeout = 10 * time.Minute
+)
 
 // LangfuseProvider defines the provider implementation.
 type LangfuseProvider struct {
@@ -29,6 +48,81 @@ type LangfuseProviderModel struct {
 	ApiHost   types.String `tfsdk:"api_host"`
 	SecretKey types.String `tfsdk:"secret_key"`
 	PublicKey types.String `tfsdk:"public_key"`
+
+	SecretKeyFile     types.String `tfsdk:"secret_key_file"`
+	PublicKeyFile     types.String `tfsdk:"public_key_file"`
+	CredentialProcess types.String `tfsdk:"credential_process"`
+
+	Profile         types.String `tfsdk:"profile"`
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+
+	ProjectCredentials  types.Map  `tfsdk:"project_credentials"`
+	ValidateCredentials types.Bool `tfsdk:"validate_credentials"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+
+	ProxyURL types.String `tfsdk:"proxy_url"`
+	Headers  types.Map    `tfsdk:"headers"`
+
+	RequestTimeout types.String `tfsdk:"request_timeout"`
+
+	DeletionProtection types.Bool `tfsdk:"deletion_protection"`
+	DefaultMetadata    types.Map  `tfsdk:"default_metadata"`
+}
+
+// projectCredentialsModel describes the keys of a project in
+// project_credentials
+type projectCredentialsModel struct {
+	PublicKey types.String `tfsdk:"public_key"`
+	SecretKey types.String `tfsdk:"secret_key"`
+}
+
+// projectCredentialsFromModel returns the keys of project_credentials by
+// project ID. Keys that are not known yet, such as keys of a project API key
+// created in the same run, are skipped, so operations during the plan use a
+// temporary key instead.
+func projectCredentialsFromModel(ctx context.Context, value types.Map) (map[string]ProjectCredentials, diag.Diagnostics) {
+	projectCredentials := make(map[string]ProjectCredentials)
+	if value.IsNull() || value.IsUnknown() {
+		return projectCredentials, nil
+	}
+
+	var elements map[string]types.Object
+	diags := value.ElementsAs(ctx, &elements, false)
+
+	for projectID, element := range elements {
+		var keys projectCredentialsModel
+		if !element.IsUnknown() {
+			diags.Append(element.As(ctx, &keys, basetypes.ObjectAsOptions{})...)
+		}
+
+		if element.IsUnknown() || keys.PublicKey.IsUnknown() || keys.SecretKey.IsUnknown() {
+			tflog.Debug(ctx, "Skipping project credentials that are not known yet", map[string]interface{}{"project_id": projectID})
+			continue
+		}
+
+		projectCredentials[projectID] = ProjectCredentials{
+			PublicKey: keys.PublicKey.ValueString(),
+			SecretKey: keys.SecretKey.ValueString(),
+		}
+	}
+
+	return projectCredentials, diags
+}
+
+// ResourceDefaults holds provider-level defaults for resource attributes
+type ResourceDefaults struct {
+	// DeletionProtection is the default of deletion_protection for resources
+	// that support it, or nil to use the default of each resource
+	DeletionProtection *bool
+
+	// Metadata is merged into the metadata of every project, with the
+	// metadata of the project taking precedence
+	Metadata map[string]string
 }
 
 func (p *LangfuseProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -40,22 +134,152 @@ func (p *LangfuseProvider) Schema(ctx context.Context, req provider.SchemaReques
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"api_host": schema.StringAttribute{
-				MarkdownDescription: "Langfuse API host URL",
+				MarkdownDescription: "Langfuse API host URL. Can also be set with the `LANGFUSE_API_HOST`, `LANGFUSE_HOST` or `LANGFUSE_BASE_URL` environment variables, or with `host` in a credentials profile.",
 				Optional:            true,
 			},
 			"secret_key": schema.StringAttribute{
-				MarkdownDescription: "Langfuse secret key for authentication",
+				MarkdownDescription: "Langfuse secret key for authentication. Can also be set with the `LANGFUSE_SECRET_KEY` environment variable, or with `secret_key` in a credentials profile.",
 				Optional:            true,
 				Sensitive:           true,
 			},
 			"public_key": schema.StringAttribute{
-				MarkdownDescription: "Langfuse public key for authentication",
+				MarkdownDescription: "Langfuse public key for authentication. Can also be set with the `LANGFUSE_PUBLIC_KEY` environment variable, or with `public_key` in a credentials profile.",
+				Optional:            true,
+			},
+			"secret_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a file holding the Langfuse secret key, for example one mounted from a secrets manager. Conflicts with `secret_key`. Can also be set with the `LANGFUSE_SECRET_KEY_FILE` environment variable.",
+				Optional:            true,
+			},
+			"public_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a file holding the Langfuse public key. Conflicts with `public_key`. Can also be set with the `LANGFUSE_PUBLIC_KEY_FILE` environment variable.",
+				Optional:            true,
+			},
+			"credential_process": schema.StringAttribute{
+				MarkdownDescription: "Command that prints the Langfuse credentials as JSON with `public_key`, `secret_key` and optionally `host` and `expires_at`. The command is run by the shell, and the credentials are cached and refreshed shortly before `expires_at`. Conflicts with the key attributes. Can also be set with the `LANGFUSE_CREDENTIAL_PROCESS` environment variable.",
+				Optional:            true,
+			},
+			"validate_credentials": schema.BoolAttribute{
+				MarkdownDescription: "Check the credentials with a cheap authenticated request when the provider is configured, and report bad keys, a project key in place of an organization key or an unreachable host against the attribute to fix. A successful check is remembered for an hour. Can also be set with the `LANGFUSE_VALIDATE_CREDENTIALS` environment variable. Defaults to `false`.",
+				Optional:            true,
+			},
+			"project_credentials": schema.MapNestedAttribute{
+				MarkdownDescription: "Project-scoped API keys by project ID, used by data sources that read data inside a project, such as `langfuse_score_configs`. For projects without keys here, the provider creates a temporary project key with the organization key for each operation and deletes it when the operation ends.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"public_key": schema.StringAttribute{
+							MarkdownDescription: "Public key of the project API key",
+							Required:            true,
+						},
+						"secret_key": schema.StringAttribute{
+							MarkdownDescription: "Secret key of the project API key",
+							Required:            true,
+							Sensitive:           true,
+						},
+					},
+				},
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Name of the profile in the credentials file to read the host and keys from. Can also be set with the `LANGFUSE_PROFILE` environment variable. Defaults to `default`, which is only used when it exists.",
+				Optional:            true,
+			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Path of the credentials file with named profiles, in INI or YAML format. Can also be set with the `LANGFUSE_CREDENTIALS_FILE` environment variable. Defaults to `~/.config/langfuse/credentials`, or `$XDG_CONFIG_HOME/langfuse/credentials` when `XDG_CONFIG_HOME` is set.",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates to trust in addition to the system roots. Can also be set with the `LANGFUSE_CA_CERT_PEM` environment variable.",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file with PEM encoded CA certificates to trust in addition to the system roots. Can also be set with the `LANGFUSE_CA_CERT_FILE` environment variable.",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client certificate for mutual TLS. Requires `client_key`. Can also be set with the `LANGFUSE_CLIENT_CERT` environment variable.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded private key for the client certificate. Requires `client_cert`. Can also be set with the `LANGFUSE_CLIENT_KEY` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip verification of the Langfuse server certificate. Only use this for testing. Can also be set with the `LANGFUSE_INSECURE_SKIP_VERIFY` environment variable.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy used to reach the Langfuse API. Can also be set with the `LANGFUSE_PROXY_URL` environment variable. Defaults to the proxy selected by the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+				Optional:            true,
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "Additional HTTP headers sent with every request, for example credentials required by an identity-aware proxy in front of Langfuse. The `Authorization` header is reserved for Langfuse authentication.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration of a single request to the Langfuse API, as a Go duration string such as `30s` or `2m`. Defaults to `30s`. Can also be set with the `LANGFUSE_REQUEST_TIMEOUT` environment variable. Resource operations are bounded separately by their `timeouts` block.",
+				Optional:            true,
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Default of `deletion_protection` for resources that support it and do not set it themselves, for example to lock a whole workspace. Can also be set with the `LANGFUSE_DELETION_PROTECTION` environment variable.",
+				Optional:            true,
+			},
+			"default_metadata": schema.MapAttribute{
+				MarkdownDescription: "Metadata added to every project, for example an owner or environment. Metadata set on a project overrides these values. The merged result is shown in the `metadata_all` attribute of each project.",
+				ElementType:         types.StringType,
 				Optional:            true,
 			},
 		},
 	}
 }
 
+func (p *LangfuseProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(path.MatchRoot("secret_key"), path.MatchRoot("secret_key_file")),
+		providervalidator.Conflicting(path.MatchRoot("public_key"), path.MatchRoot("public_key_file")),
+		providervalidator.Conflicting(path.MatchRoot("credential_process"), path.MatchRoot("secret_key")),
+		providervalidator.Conflicting(path.MatchRoot("credential_process"), path.MatchRoot("secret_key_file")),
+		providervalidator.Conflicting(path.MatchRoot("credential_process"), path.MatchRoot("public_key")),
+		providervalidator.Conflicting(path.MatchRoot("credential_process"), path.MatchRoot("public_key_file")),
+	}
+}
+
+// credentialsProfile reads the selected profile from the credentials file.
+// Only an explicitly selected profile has to exist.
+func (p *LangfuseProvider) credentialsProfile(data LangfuseProviderModel) (credentialsProfile, error) {
+	name := os.Getenv("LANGFUSE_PROFILE")
+	if !data.Profile.IsNull() {
+		name = data.Profile.ValueString()
+	}
+
+	filename := os.Getenv("LANGFUSE_CREDENTIALS_FILE")
+	if !data.CredentialsFile.IsNull() {
+		filename = data.CredentialsFile.ValueString()
+	}
+
+	profileRequired := name != ""
+	fileRequired := filename != ""
+
+	if name == "" {
+		name = defaultProfile
+	}
+
+	if filename == "" {
+		defaultFilename, err := defaultCredentialsFile()
+		if err != nil {
+			if !profileRequired {
+				return credentialsProfile{}, nil
+			}
+			return credentialsProfile{}, fmt.Errorf("error finding the credentials file: %w", err)
+		}
+		filename = defaultFilename
+	}
+
+	return loadCredentialsProfile(filename, name, fileRequired, profileRequired)
+}
+
 func (p *LangfuseProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data LangfuseProviderModel
 
@@ -68,23 +292,90 @@ func (p *LangfuseProvider) Configure(ctx context.Context, req provider.Configure
 	// Configuration values are now available.
 	// Example code to configure a HTTP client...
 
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
+	// Default values to the credentials profile, override them with
+	// environment variables and those with Terraform configuration values
+	// if set.
 
-	apiHost := os.Getenv("LANGFUSE_API_HOST")
-	secretKey := os.Getenv("LANGFUSE_SECRET_KEY")
-	publicKey := os.Getenv("LANGFUSE_PUBLIC_KEY")
+	profile, err := p.credentialsProfile(data)
+	if err != nil {
+		attributePath := path.Root("profile")
+		if !data.CredentialsFile.IsNull() {
+			attributePath = path.Root("credentials_file")
+		}
 
-	if !data.ApiHost.IsNull() {
-		apiHost = data.ApiHost.ValueString()
+		resp.Diagnostics.AddAttributeError(
+			attributePath,
+			"Unable to Read Langfuse Credentials Profile",
+			fmt.Sprintf("The provider cannot read the Langfuse credentials profile: %s", err),
+		)
+		return
 	}
 
-	if !data.SecretKey.IsNull() {
-		secretKey = data.SecretKey.ValueString()
+	apiHost := profile.Host
+	secretKey := profile.SecretKey
+	publicKey := profile.PublicKey
+
+	// LANGFUSE_HOST and LANGFUSE_BASE_URL are the names used by the
+	// Langfuse SDKs, LANGFUSE_API_HOST takes precedence over them
+	for _, name := range []string{"LANGFUSE_BASE_URL", "LANGFUSE_HOST", "LANGFUSE_API_HOST"} {
+		if v := os.Getenv(name); v != "" {
+			apiHost = v
+		}
 	}
 
-	if !data.PublicKey.IsNull() {
-		publicKey = data.PublicKey.ValueString()
+	// At each level, a key takes precedence over a key file
+	secretKey, err = resolveKey(secretKey, data.SecretKey, data.SecretKeyFile, "LANGFUSE_SECRET_KEY")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("secret_key_file"),
+			"Unable to Read Langfuse Secret Key File",
+			fmt.Sprintf("The provider cannot read the Langfuse secret key: %s", err),
+		)
+	}
+
+	publicKey, err = resolveKey(publicKey, data.PublicKey, data.PublicKeyFile, "LANGFUSE_PUBLIC_KEY")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("public_key_file"),
+			"Unable to Read Langfuse Public Key File",
+			fmt.Sprintf("The provider cannot read the Langfuse public key: %s", err),
+		)
+	}
+
+	// A credential process set in the environment is ignored when keys are
+	// configured, while one set in the configuration conflicts with them
+	processCommand := os.Getenv("LANGFUSE_CREDENTIAL_PROCESS")
+	if !data.SecretKey.IsNull() || !data.SecretKeyFile.IsNull() || !data.PublicKey.IsNull() || !data.PublicKeyFile.IsNull() {
+		processCommand = ""
+	}
+
+	if !data.CredentialProcess.IsNull() {
+		processCommand = data.CredentialProcess.ValueString()
+	}
+
+	var process *credentialProcess
+	if processCommand != "" {
+		process = newCredentialProcess(processCommand)
+
+		credentials, err := process.Retrieve(ctx)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_process"),
+				"Unable to Run Langfuse Credential Process",
+				fmt.Sprintf("The provider cannot get the Langfuse credentials from the credential process: %s", err),
+			)
+			return
+		}
+
+		secretKey = credentials.SecretKey
+		publicKey = credentials.PublicKey
+		if credentials.Host != "" {
+			apiHost = credentials.Host
+		}
+	}
+
+	if !data.ApiHost.IsNull() {
+		apiHost = data.ApiHost.ValueString()
 	}
 
 	// If any of the expected configurations are missing, return
@@ -95,7 +386,7 @@ func (p *LangfuseProvider) Configure(ctx context.Context, req provider.Configure
 			path.Root("api_host"),
 			"Missing Langfuse API Host",
 			"The provider cannot create the Langfuse API client as there is a missing or empty value for the Langfuse API host. "+
-				"Set the api_host value in the configuration or use the LANGFUSE_API_HOST environment variable. "+
+				"Set the api_host value in the configuration, use the LANGFUSE_API_HOST environment variable or set host in a credentials profile. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -105,7 +396,7 @@ func (p *LangfuseProvider) Configure(ctx context.Context, req provider.Configure
 			path.Root("secret_key"),
 			"Missing Langfuse Secret Key",
 			"The provider cannot create the Langfuse API client as there is a missing or empty value for the Langfuse secret key. "+
-				"Set the secret_key value in the configuration or use the LANGFUSE_SECRET_KEY environment variable. "+
+				"Set the secret_key or secret_key_file value in the configuration, use the LANGFUSE_SECRET_KEY environment variable, set secret_key in a credentials profile or use a credential process. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -115,29 +406,213 @@ func (p *LangfuseProvider) Configure(ctx context.Context, req provider.Configure
 			path.Root("public_key"),
 			"Missing Langfuse Public Key",
 			"The provider cannot create the Langfuse API client as there is a missing or empty value for the Langfuse public key. "+
-				"Set the public_key value in the configuration or use the LANGFUSE_PUBLIC_KEY environment variable. "+
+				"Set the public_key or public_key_file value in the configuration, use the LANGFUSE_PUBLIC_KEY environment variable, set public_key in a credentials profile or use a credential process. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	transportOptions := TransportOptions{
+		CACertPEM:  os.Getenv("LANGFUSE_CA_CERT_PEM"),
+		CACertFile: os.Getenv("LANGFUSE_CA_CERT_FILE"),
+		ClientCert: os.Getenv("LANGFUSE_CLIENT_CERT"),
+		ClientKey:  os.Getenv("LANGFUSE_CLIENT_KEY"),
+		ProxyURL:   os.Getenv("LANGFUSE_PROXY_URL"),
+	}
+
+	if v := os.Getenv("LANGFUSE_INSECURE_SKIP_VERIFY"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure_skip_verify"),
+				"Invalid LANGFUSE_INSECURE_SKIP_VERIFY Value",
+				fmt.Sprintf("The LANGFUSE_INSECURE_SKIP_VERIFY environment variable must be a boolean, got %q.", v),
+			)
+		}
+		transportOptions.InsecureSkipVerify = insecure
+	}
+
+	if !data.CACertPEM.IsNull() {
+		transportOptions.CACertPEM = data.CACertPEM.ValueString()
+	}
+
+	if !data.CACertFile.IsNull() {
+		transportOptions.CACertFile = data.CACertFile.ValueString()
+	}
+
+	if !data.ClientCert.IsNull() {
+		transportOptions.ClientCert = data.ClientCert.ValueString()
+	}
+
+	if !data.ClientKey.IsNull() {
+		transportOptions.ClientKey = data.ClientKey.ValueString()
+	}
+
+	if !data.InsecureSkipVerify.IsNull() {
+		transportOptions.InsecureSkipVerify = data.InsecureSkipVerify.ValueBool()
+	}
+
+	if !data.ProxyURL.IsNull() {
+		transportOptions.ProxyURL = data.ProxyURL.ValueString()
+	}
+
+	if transportOptions.ProxyURL != "" {
+		if _, err := parseProxyURL(transportOptions.ProxyURL); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Langfuse Proxy URL",
+				fmt.Sprintf("The provider cannot create the Langfuse API client as the proxy URL is invalid: %s", err),
+			)
+		}
+	}
+
+	headers := make(map[string]string)
+	if !data.Headers.IsNull() {
+		resp.Diagnostics.Append(data.Headers.ElementsAs(ctx, &headers, false)...)
+	}
+
+	headerNames := make([]string, 0, len(headers))
+	headerValues := make([]string, 0, len(headers))
+	for name, value := range headers {
+		if name == "" || strings.EqualFold(name, "Authorization") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("headers").AtMapKey(name),
+				"Invalid Langfuse Header",
+				fmt.Sprintf("The header %q cannot be set, as it is empty or reserved for Langfuse authentication.", name),
+			)
+			continue
+		}
+		headerNames = append(headerNames, name)
+		if value != "" {
+			headerValues = append(headerValues, value)
+		}
+	}
+
+	if (transportOptions.ClientCert == "") != (transportOptions.ClientKey == "") {
+		missing := "client_cert"
+		if transportOptions.ClientKey == "" {
+			missing = "client_key"
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root(missing),
+			"Incomplete Langfuse Client Certificate",
+			"Mutual TLS requires both client_cert and client_key. "+
+				"Set both values in the configuration or with the LANGFUSE_CLIENT_CERT and LANGFUSE_CLIENT_KEY environment variables.",
+		)
+	}
+
+	requestTimeout := defaultRequestTimeout
+	requestTimeoutValue := os.Getenv("LANGFUSE_REQUEST_TIMEOUT")
+
+	if !data.RequestTimeout.IsNull() {
+		requestTimeoutValue = data.RequestTimeout.ValueString()
+	}
+
+	if requestTimeoutValue != "" {
+		timeout, err := time.ParseDuration(requestTimeoutValue)
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid Langfuse Request Timeout",
+				fmt.Sprintf("The request timeout must be a positive duration such as \"30s\" or \"2m\", got %q.", requestTimeoutValue),
+			)
+		}
+		requestTimeout = timeout
+	}
+
+	var defaults ResourceDefaults
+
+	if v := os.Getenv("LANGFUSE_DELETION_PROTECTION"); v != "" {
+		deletionProtection, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("deletion_protection"),
+				"Invalid LANGFUSE_DELETION_PROTECTION Value",
+				fmt.Sprintf("The LANGFUSE_DELETION_PROTECTION environment variable must be a boolean, got %q.", v),
+			)
+		}
+		defaults.DeletionProtection = &deletionProtection
+	}
+
+	if !data.DeletionProtection.IsNull() {
+		deletionProtection := data.DeletionProtection.ValueBool()
+		defaults.DeletionProtection = &deletionProtection
+	}
+
+	if !data.DefaultMetadata.IsNull() {
+		resp.Diagnostics.Append(data.DefaultMetadata.ElementsAs(ctx, &defaults.Metadata, false)...)
+	}
+
+	var validateCredentials bool
+	if v := os.Getenv("LANGFUSE_VALIDATE_CREDENTIALS"); v != "" {
+		validate, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("validate_credentials"),
+				"Invalid LANGFUSE_VALIDATE_CREDENTIALS Value",
+				fmt.Sprintf("The LANGFUSE_VALIDATE_CREDENTIALS environment variable must be a boolean, got %q.", v),
+			)
+		}
+		validateCredentials = validate
+	}
+
+	if !data.ValidateCredentials.IsNull() {
+		validateCredentials = data.ValidateCredentials.ValueBool()
+	}
+
+	projectCredentials, diags := projectCredentialsFromModel(ctx, data.ProjectCredentials)
+	resp.Diagnostics.Append(diags...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	transport, err := newTransport(transportOptions)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Langfuse TLS Configuration",
+			fmt.Sprintf("The provider cannot create the Langfuse API client as the TLS configuration is invalid: %s", err),
+		)
+		return
+	}
+
+	if transportOptions.InsecureSkipVerify {
+		tflog.Warn(ctx, "TLS certificate verification of the Langfuse API host is disabled")
+	}
+
 	ctx = tflog.SetField(ctx, "langfuse_api_host", apiHost)
 	ctx = tflog.SetField(ctx, "langfuse_secret_key", secretKey)
 	ctx = tflog.SetField(ctx, "langfuse_public_key", publicKey)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "langfuse_secret_key")
+	ctx = tflog.SetField(ctx, "langfuse_headers", headerNames)
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, headerValues...)
+	ctx = tflog.MaskMessageStrings(ctx, headerValues...)
 
 	tflog.Debug(ctx, "Creating Langfuse client")
 
 	// Create a new Langfuse client using the configuration values
-	client := NewClient(apiHost, secretKey, publicKey)
+	clientOptions := []ClientOption{WithTransport(transport), WithHeaders(headers), WithRequestTimeout(requestTimeout)}
+	if process != nil {
+		clientOptions = append(clientOptions, WithCredentialProcess(process))
+	}
+	if len(projectCredentials) > 0 {
+		clientOptions = append(clientOptions, WithProjectCredentials(projectCredentials))
+	}
+
+	client := NewClient(apiHost, secretKey, publicKey, clientOptions...)
+	client.Defaults = defaults
+
+	if validateCredentials {
+		if err := client.CheckCredentials(ctx); err != nil {
+			addCredentialsCheckError(&resp.Diagnostics, apiHost, err, process != nil)
+			return
+		}
+	}
 
-	// Make the Langfuse client available during DataSource and Resource
-	// type Configure methods.
+	// Make the Langfuse client available during DataSource, Resource and
+	// EphemeralResource type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
 
 	tflog.Info(ctx, "Configured Langfuse client", map[string]any{"success": true})
 }
@@ -149,9 +624,15 @@ func (p *LangfuseProvider) Resources(ctx context.Context) []func() resource.Reso
 	}
 }
 
+func (p *LangfuseProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewProjectApiKeyEphemeralResource,
+	}
+}
+
 func (p *LangfuseProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		// Add data sources here if needed
+		NewScoreConfigsDataSource,
 	}
 }
 
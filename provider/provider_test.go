@@ -1,10 +1,25 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	pathpkg "path"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -23,4 +38,361 @@ func TestAccProvider(t *testing.T) {
 func testAccPreCheck(t *testing.T) {
 	// Add pre-check logic here if needed for acceptance tests
 	// For example, checking that required environment variables are set
-} 
\ No newline at end of file
+}
+
+// resourceSchema returns the schema of a resource
+func resourceSchema(t *testing.T, r resource.Resource) schema.Schema {
+	t.Helper()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	return schemaResp.Schema
+}
+
+// newTestProjectResource returns a project resource configured with a
+// client for the given server, together with its schema
+func newTestProjectResource(t *testing.T, serverURL string) (*ProjectResource, schema.Schema) {
+	t.Helper()
+
+	r := &ProjectResource{client: NewClient(serverURL, "sk", "pk")}
+	return r, resourceSchema(t, r)
+}
+
+// newTestApiKeyResource returns an API key resource configured with a
+// client for the given server, together with its schema
+func newTestApiKeyResource(t *testing.T, serverURL string) (*ProjectApiKeyResource, schema.Schema) {
+	t.Helper()
+
+	r := &ProjectApiKeyResource{client: NewClient(serverURL, "sk", "pk")}
+	return r, resourceSchema(t, r)
+}
+
+// emptyState returns a null state for the given schema
+func emptyState(s schema.Schema) tfsdk.State {
+	return tfsdk.State{
+		Schema: s,
+		Raw:    tftypes.NewValue(s.Type().TerraformType(context.Background()), nil),
+	}
+}
+
+// destroyPlan returns the null plan of a resource being destroyed
+func destroyPlan(s schema.Schema) tfsdk.Plan {
+	return tfsdk.Plan{
+		Schema: s,
+		Raw:    tftypes.NewValue(s.Type().TerraformType(context.Background()), nil),
+	}
+}
+
+// nullTimeouts returns a null timeouts value for the given schema
+func nullTimeouts(t *testing.T, s schema.Schema) timeouts.Value {
+	t.Helper()
+
+	timeoutsType, diags := s.TypeAtPath(context.Background(), path.Root("timeouts"))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	return timeouts.Value{
+		Object: types.ObjectNull(timeoutsType.(timeouts.Type).AttrTypes),
+	}
+}
+
+// stateFromModel returns a state for the given schema holding the model
+func stateFromModel(t *testing.T, s schema.Schema, model interface{}) tfsdk.State {
+	t.Helper()
+
+	state := emptyState(s)
+	if diags := state.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	return state
+}
+
+// planFromModel returns a plan for the given schema holding the model
+func planFromModel(t *testing.T, s schema.Schema, model interface{}) tfsdk.Plan {
+	t.Helper()
+
+	state := stateFromModel(t, s, model)
+	return tfsdk.Plan{Schema: s, Raw: state.Raw}
+}
+
+// applyResourceChange plans and applies an update of a resource through the
+// provider server, as Terraform does, and returns the new state. The
+// configuration holds the prior state values of all but computed-only
+// attributes, with the given attributes changed.
+func applyResourceChange(t *testing.T, server tfprotov6.ProviderServer, typeName string, s schema.Schema, prior tfsdk.State, changes map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	ctx := context.Background()
+	objectType := s.Type().TerraformType(ctx).(tftypes.Object)
+
+	var priorValues map[string]tftypes.Value
+	if err := prior.Raw.As(&priorValues); err != nil {
+		t.Fatal(err)
+	}
+
+	configValues := make(map[string]tftypes.Value, len(priorValues))
+	proposedValues := make(map[string]tftypes.Value, len(priorValues))
+	for name, value := range priorValues {
+		configValues[name] = value
+		proposedValues[name] = value
+		if attribute, ok := s.Attributes[name]; ok && attribute.IsComputed() && !attribute.IsOptional() {
+			configValues[name] = tftypes.NewValue(objectType.AttributeTypes[name], nil)
+		}
+	}
+	for name, value := range changes {
+		configValues[name] = value
+		proposedValues[name] = value
+	}
+
+	// Like Terraform, propose the prior value of optional computed
+	// attributes that the configuration leaves null
+	for name, attribute := range s.Attributes {
+		if attribute.IsComputed() && attribute.IsOptional() && configValues[name].IsNull() {
+			proposedValues[name] = priorValues[name]
+		}
+	}
+
+	dynamicValue := func(value tftypes.Value) *tfprotov6.DynamicValue {
+		dv, err := tfprotov6.NewDynamicValue(objectType, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &dv
+	}
+	failOnError := func(step string, diags []*tfprotov6.Diagnostic) {
+		for _, d := range diags {
+			if d.Severity == tfprotov6.DiagnosticSeverityError {
+				t.Fatalf("unexpected %s error: %s: %s", step, d.Summary, d.Detail)
+			}
+		}
+	}
+
+	priorState := dynamicValue(prior.Raw)
+	config := dynamicValue(tftypes.NewValue(objectType, configValues))
+
+	planResp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       priorState,
+		ProposedNewState: dynamicValue(tftypes.NewValue(objectType, proposedValues)),
+		Config:           config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failOnError("plan", planResp.Diagnostics)
+
+	applyResp, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     priorState,
+		PlannedState:   planResp.PlannedState,
+		Config:         config,
+		PlannedPrivate: planResp.PlannedPrivate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failOnError("apply", applyResp.Diagnostics)
+
+	newState, err := applyResp.NewState.Unmarshal(objectType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newState
+}
+
+// validateResourceConfig validates a resource configuration as terraform
+// validate does, with all attributes not given set to null, and returns
+// whether it is valid
+func validateResourceConfig(t *testing.T, r resource.Resource, typeName string, attributes map[string]tftypes.Value) bool {
+	t.Helper()
+
+	ctx := context.Background()
+	configType := resourceSchema(t, r).Type().TerraformType(ctx).(tftypes.Object)
+
+	values := make(map[string]tftypes.Value, len(configType.AttributeTypes))
+	for name, attrType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+
+	config, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := providerserver.NewProtocol6(New("test")())()
+	resp, err := server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{TypeName: typeName, Config: &config})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestResourceValidateConfig(t *testing.T) {
+	str := func(value string) tftypes.Value { return tftypes.NewValue(tftypes.String, value) }
+	num := func(value int64) tftypes.Value { return tftypes.NewValue(tftypes.Number, value) }
+
+	projectID := str("clkv6g5jo0000jz088vzn1ja4")
+
+	testCases := map[string]struct {
+		resource   resource.Resource
+		typeName   string
+		attributes map[string]tftypes.Value
+		valid      bool
+	}{
+		"project": {
+			&ProjectResource{}, "langfuse_project",
+			map[string]tftypes.Value{"name": str("checkout-service"), "retention_days": num(30)}, true,
+		},
+		"project retention disabled": {
+			&ProjectResource{}, "langfuse_project",
+			map[string]tftypes.Value{"name": str("checkout-service"), "retention_days": num(0)}, true,
+		},
+		"project retention too short": {
+			&ProjectResource{}, "langfuse_project",
+			map[string]tftypes.Value{"name": str("checkout-service"), "retention_days": num(2)}, false,
+		},
+		"project empty name": {
+			&ProjectResource{}, "langfuse_project",
+			map[string]tftypes.Value{"name": str("")}, false,
+		},
+		"project metadata not an object": {
+			&ProjectResource{}, "langfuse_project",
+			map[string]tftypes.Value{"name": str("checkout-service"), "metadata_json": str(`["payments"]`)}, false,
+		},
+		"api key": {
+			&ProjectApiKeyResource{}, "langfuse_project_api_key",
+			map[string]tftypes.Value{"project_id": projectID, "note": str("CI"), "rotation_days": num(90), "rotation_grace_days": num(7)}, true,
+		},
+		"api key project name as ID": {
+			&ProjectApiKeyResource{}, "langfuse_project_api_key",
+			map[string]tftypes.Value{"project_id": str("checkout service")}, false,
+		},
+		"api key empty note": {
+			&ProjectApiKeyResource{}, "langfuse_project_api_key",
+			map[string]tftypes.Value{"project_id": projectID, "note": str("")}, true,
+		},
+		"api key long note": {
+			&ProjectApiKeyResource{}, "langfuse_project_api_key",
+			map[string]tftypes.Value{"project_id": projectID, "note": str(strings.Repeat("x", 1001))}, false,
+		},
+		"api key grace without rotation": {
+			&ProjectApiKeyResource{}, "langfuse_project_api_key",
+			map[string]tftypes.Value{"project_id": projectID, "rotation_grace_days": num(7)}, false,
+		},
+		"api key grace longer than rotation": {
+			&ProjectApiKeyResource{}, "langfuse_project_api_key",
+			map[string]tftypes.Value{"project_id": projectID, "rotation_days": num(7), "rotation_grace_days": num(30)}, false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		if valid := validateResourceConfig(t, testCase.resource, testCase.typeName, testCase.attributes); valid != testCase.valid {
+			t.Errorf("%s: expected valid to be %t", name, testCase.valid)
+		}
+	}
+}
+
+// fakeProjectsServer serves the organization project list and applies
+// project creates and updates to it like Langfuse does. It records the
+// requests it receives.
+type fakeProjectsServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	projects []map[string]interface{}
+
+	listCalls int64
+	created   []string
+	updated   []string
+	updates   []map[string]interface{}
+}
+
+// newFakeProjectsServer starts a fake server holding the given projects
+func newFakeProjectsServer(tb testing.TB, projects ...map[string]interface{}) *fakeProjectsServer {
+	tb.Helper()
+
+	f := &fakeProjectsServer{projects: projects}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/public/organizations/projects":
+			atomic.AddInt64(&f.listCalls, 1)
+			json.NewEncoder(w).Encode(map[string]interface{}{"projects": f.projects})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/public/projects":
+			var project map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
+				tb.Errorf("invalid create request: %s", err)
+			}
+			project["id"] = "project-new"
+			project["createdAt"] = "2024-06-01T00:00:00.000Z"
+			applyRetention(project)
+			f.created = append(f.created, "project-new")
+			f.projects = append(f.projects, project)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(project)
+		case r.Method == http.MethodPut:
+			id := pathpkg.Base(r.URL.Path)
+			project := f.project(id)
+			if project == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			var update map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				tb.Errorf("invalid update request: %s", err)
+			}
+			f.updated = append(f.updated, id)
+			f.updates = append(f.updates, update)
+			for key, value := range update {
+				project[key] = value
+			}
+			applyRetention(project)
+			json.NewEncoder(w).Encode(project)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	tb.Cleanup(f.Close)
+
+	return f
+}
+
+// project returns the stored project with the given ID
+func (f *fakeProjectsServer) project(id string) map[string]interface{} {
+	for _, project := range f.projects {
+		if project["id"] == id {
+			return project
+		}
+	}
+
+	return nil
+}
+
+// applyRetention moves the retention sent in a request to retentionDays, as
+// Langfuse returns it
+func applyRetention(project map[string]interface{}) {
+	if retention, ok := project["retention"]; ok {
+		project["retentionDays"] = retention
+		delete(project, "retention")
+	}
+}
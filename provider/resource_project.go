@@ -3,13 +3,19 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/cirobaronneto/terraform-provider-langfuse/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -17,6 +23,9 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ProjectResource{}
 var _ resource.ResourceWithImportState = &ProjectResource{}
+var _ resource.ResourceWithModifyPlan = &ProjectResource{}
+var _ resource.ResourceWithConfigValidators = &ProjectResource{}
+var _ resource.ResourceWithUpgradeState = &ProjectResource{}
 
 func NewProjectResource() resource.Resource {
 	return &ProjectResource{}
@@ -35,6 +44,23 @@ type ProjectResourceModel struct {
 	RetentionDays types.Int64  `tfsdk:"retention_days"`
 	CreatedAt     types.String `tfsdk:"created_at"`
 	UpdatedAt     types.String `tfsdk:"updated_at"`
+
+	MetadataJSON jsontypes.Normalized `tfsdk:"metadata_json"`
+	MetadataAll  types.Map            `tfsdk:"metadata_all"`
+
+	WaitForDeletion types.Bool `tfsdk:"wait_for_deletion"`
+
+	DeletionProtection types.Bool `tfsdk:"deletion_protection"`
+	AdoptExisting      types.Bool `tfsdk:"adopt_existing"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// projectAPIFields maps Langfuse API request fields to resource attributes
+var projectAPIFields = map[string]path.Path{
+	"name":      path.Root("name"),
+	"metadata":  path.Root("metadata"),
+	"retention": path.Root("retention_days"),
 }
 
 func (r *ProjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -46,6 +72,9 @@ func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Langfuse project resource",
 
+		// Version 1 added metadata_json
+		Version: 1,
+
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
@@ -55,30 +84,86 @@ func (r *ProjectResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Project name",
+				MarkdownDescription: "Project name, with between 3 and 60 characters",
 				Required:            true,
+				Validators: []validator.String{
+					validators.ProjectName(),
+				},
 			},
 			"metadata": schema.MapAttribute{
-				MarkdownDescription: "Project metadata",
+				MarkdownDescription: "Project metadata with string values. Conflicts with `metadata_json`.",
 				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"metadata_json": schema.StringAttribute{
+				MarkdownDescription: "Project metadata as a JSON object, for metadata with values other than strings such as numbers, booleans or nested objects. Differences in whitespace or key order are ignored. Conflicts with `metadata`. When `metadata` is used instead, this shows the stored metadata as JSON, and when neither is set the project has no metadata.",
+				CustomType:          jsontypes.NormalizedType{},
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					validators.JSONObject(),
+				},
+			},
+			"metadata_all": schema.MapAttribute{
+				MarkdownDescription: "All string metadata of the project, including the provider's `default_metadata`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
 			"retention_days": schema.Int64Attribute{
-				MarkdownDescription: "Number of days to retain data. Must be 0 or at least 3 days.",
+				MarkdownDescription: "Number of days to retain data. Must be 0 or at least 3 days. When unset or 0, data retention is disabled and data is kept indefinitely.",
 				Optional:            true,
+				Validators: []validator.Int64{
+					validators.RetentionDays(),
+				},
 			},
 			"created_at": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Project creation timestamp",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"updated_at": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Project last update timestamp",
 			},
+			"wait_for_deletion": schema.BoolAttribute{
+				MarkdownDescription: "Wait until Langfuse has finished deleting the project when it is destroyed, bounded by the delete timeout. Langfuse deletes projects asynchronously, so disabling this can race with creating a project of the same name or deleting the organization. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Prevent the project and all of its data from being deleted. Destroying the project fails until this is set to `false` and applied. Defaults to the provider's `deletion_protection`, or `true` when that is not set.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "When creating the project, adopt an existing project with exactly the same name instead of creating a duplicate, and update it to match the configuration. Creation fails when several projects have the name. Useful to recover from lost state or a timed out create.",
+				Optional:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+func (r *ProjectResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("metadata"),
+			path.MatchRoot("metadata_json"),
+		),
+	}
+}
+
 func (r *ProjectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -109,21 +194,29 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Create API request
 	createReq := CreateProjectRequest{
 		Name: data.Name.ValueString(),
 	}
 
 	// Handle metadata
-	if !data.Metadata.IsNull() && !data.Metadata.IsUnknown() {
-		metadataMap := make(map[string]interface{})
-		for key, value := range data.Metadata.Elements() {
-			if strValue, ok := value.(types.String); ok {
-				metadataMap[key] = strValue.ValueString()
-			}
-		}
-		createReq.Metadata = metadataMap
+	metadata, diags := r.metadataFromModel(data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	createReq.Metadata = metadata
 
 	// Handle retention days
 	if !data.RetentionDays.IsNull() && !data.RetentionDays.IsUnknown() {
@@ -131,11 +224,51 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		createReq.Retention = &retention
 	}
 
+	// Adopt an existing project of the same name instead of creating a
+	// duplicate, as Langfuse does not enforce unique names
+	var project *Project
+
+	if data.AdoptExisting.ValueBool() {
+		matches, err := r.client.FindProjectsByName(ctx, createReq.Name)
+		if err != nil {
+			addClientError(&resp.Diagnostics, "look up existing projects", err, projectAPIFields)
+			return
+		}
+
+		switch len(matches) {
+		case 0:
+		case 1:
+			tflog.Info(ctx, "Adopting existing project", map[string]interface{}{"id": matches[0].ID})
+
+			project, err = r.client.UpdateProject(ctx, matches[0].ID, NewUpdateProjectRequest(createReq.Name, createReq.Metadata, createReq.Retention))
+			if err != nil {
+				addClientError(&resp.Diagnostics, "update adopted project", err, projectAPIFields)
+				return
+			}
+
+			if project.CreatedAt == "" {
+				project.CreatedAt = matches[0].CreatedAt
+			}
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("adopt_existing"),
+				"Ambiguous Project Name",
+				fmt.Sprintf("Found %d projects named %q, so none of them can be adopted: %s. Import the intended project by ID instead.",
+					len(matches), createReq.Name, strings.Join(projectIDs(matches), ", ")),
+			)
+			return
+		}
+	}
+
 	// Create project
-	project, err := r.client.CreateProject(createReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create project, got error: %s", err))
-		return
+	if project == nil {
+		var err error
+
+		project, err = r.client.CreateProject(ctx, createReq)
+		if err != nil {
+			addClientError(&resp.Diagnostics, "create project", err, projectAPIFields)
+			return
+		}
 	}
 
 	// Update model with response data
@@ -144,28 +277,10 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 	data.CreatedAt = types.StringValue(project.CreatedAt)
 	data.UpdatedAt = types.StringValue(project.UpdatedAt)
 
-	if project.RetentionDays != nil {
-		data.RetentionDays = types.Int64Value(int64(*project.RetentionDays))
-	}
+	setRetentionDays(&data, project.RetentionDays)
 
 	// Handle metadata response
-	if project.Metadata != nil && len(project.Metadata) > 0 {
-		metadataElements := make(map[string]types.String)
-		for key, value := range project.Metadata {
-			if strValue, ok := value.(string); ok {
-				metadataElements[key] = types.StringValue(strValue)
-			}
-		}
-		metadataValueMap := make(map[string]attr.Value)
-		for key, value := range metadataElements {
-			metadataValueMap[key] = value
-		}
-		metadataMap, diags := types.MapValue(types.StringType, metadataValueMap)
-		resp.Diagnostics.Append(diags...)
-		if !resp.Diagnostics.HasError() {
-			data.Metadata = metadataMap
-		}
-	}
+	resp.Diagnostics.Append(r.setMetadata(&data, project.Metadata)...)
 
 	// Write logs using the tflog package
 	tflog.Trace(ctx, "created a resource")
@@ -184,37 +299,47 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Get project from API
-	project, err := r.client.GetProject(data.ID.ValueString())
+	project, err := r.client.GetProject(ctx, data.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read project, got error: %s", err))
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "Project no longer exists, removing from state", map[string]interface{}{"id": data.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		addClientError(&resp.Diagnostics, "read project", err, projectAPIFields)
 		return
 	}
 
 	// Update model with fresh data
 	data.ID = types.StringValue(project.ID)
+
+	// wait_for_deletion is not stored by Langfuse, so imported projects
+	// start with the default
+	if data.WaitForDeletion.IsNull() {
+		data.WaitForDeletion = types.BoolValue(true)
+	}
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(r.defaultDeletionProtection())
+	}
 	data.Name = types.StringValue(project.Name)
 	data.CreatedAt = types.StringValue(project.CreatedAt)
 	data.UpdatedAt = types.StringValue(project.UpdatedAt)
 
-	if project.RetentionDays != nil {
-		data.RetentionDays = types.Int64Value(int64(*project.RetentionDays))
-	}
+	setRetentionDays(&data, project.RetentionDays)
 
 	// Handle metadata response
-	if project.Metadata != nil && len(project.Metadata) > 0 {
-		metadataValueMap := make(map[string]attr.Value)
-		for key, value := range project.Metadata {
-			if strValue, ok := value.(string); ok {
-				metadataValueMap[key] = types.StringValue(strValue)
-			}
-		}
-		metadataMap, diags := types.MapValue(types.StringType, metadataValueMap)
-		resp.Diagnostics.Append(diags...)
-		if !resp.Diagnostics.HasError() {
-			data.Metadata = metadataMap
-		}
-	}
+	resp.Diagnostics.Append(r.setMetadata(&data, project.Metadata)...)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -230,32 +355,39 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	// Create API request
-	updateReq := UpdateProjectRequest{
-		Name: data.Name.ValueString(),
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Handle metadata
-	if !data.Metadata.IsNull() && !data.Metadata.IsUnknown() {
-		metadataMap := make(map[string]interface{})
-		for key, value := range data.Metadata.Elements() {
-			if strValue, ok := value.(types.String); ok {
-				metadataMap[key] = strValue.ValueString()
-			}
-		}
-		updateReq.Metadata = metadataMap
+	metadata, diags := r.metadataFromModel(data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Handle retention days
+	var retention *int
 	if !data.RetentionDays.IsNull() && !data.RetentionDays.IsUnknown() {
-		retention := int(data.RetentionDays.ValueInt64())
-		updateReq.Retention = &retention
+		days := int(data.RetentionDays.ValueInt64())
+		retention = &days
 	}
 
+	// Create API request, sending every field so that attributes removed
+	// from the configuration are reset
+	updateReq := NewUpdateProjectRequest(data.Name.ValueString(), metadata, retention)
+
 	// Update project
-	project, err := r.client.UpdateProject(data.ID.ValueString(), updateReq)
+	project, err := r.client.UpdateProject(ctx, data.ID.ValueString(), updateReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update project, got error: %s", err))
+		addClientError(&resp.Diagnostics, "update project", err, projectAPIFields)
 		return
 	}
 
@@ -263,24 +395,10 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 	data.Name = types.StringValue(project.Name)
 	data.UpdatedAt = types.StringValue(project.UpdatedAt)
 
-	if project.RetentionDays != nil {
-		data.RetentionDays = types.Int64Value(int64(*project.RetentionDays))
-	}
+	setRetentionDays(&data, project.RetentionDays)
 
 	// Handle metadata response
-	if project.Metadata != nil && len(project.Metadata) > 0 {
-		metadataValueMap := make(map[string]attr.Value)
-		for key, value := range project.Metadata {
-			if strValue, ok := value.(string); ok {
-				metadataValueMap[key] = types.StringValue(strValue)
-			}
-		}
-		metadataMap, diags := types.MapValue(types.StringType, metadataValueMap)
-		resp.Diagnostics.Append(diags...)
-		if !resp.Diagnostics.HasError() {
-			data.Metadata = metadataMap
-		}
-	}
+	resp.Diagnostics.Append(r.setMetadata(&data, project.Metadata)...)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -296,14 +414,350 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.DeletionProtection.IsNull() || data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("deletion_protection"),
+			"Project Is Protected From Deletion",
+			fmt.Sprintf("Project %q (%s) has deletion_protection enabled, as deleting it purges all of its data. "+
+				"Set deletion_protection to false and apply that change before destroying the project.", data.Name.ValueString(), data.ID.ValueString()),
+		)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Delete project
-	err := r.client.DeleteProject(data.ID.ValueString())
+	err := r.client.DeleteProject(ctx, data.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete project, got error: %s", err))
+		if IsNotFound(err) {
+			return
+		}
+		addClientError(&resp.Diagnostics, "delete project", err, projectAPIFields)
 		return
 	}
+
+	// Langfuse deletes projects asynchronously
+	if data.WaitForDeletion.IsNull() || data.WaitForDeletion.ValueBool() {
+		if err := r.client.WaitForProjectDeletion(ctx, data.ID.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Project Deletion Not Completed",
+				fmt.Sprintf("Langfuse accepted the deletion of project %s, but it did not complete within the delete timeout: %s. "+
+					"Increase the delete timeout or set wait_for_deletion to false to skip waiting.", data.ID.ValueString(), err),
+			)
+			return
+		}
+	}
+}
+
+func (r *ProjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if !req.Plan.Raw.IsNull() {
+		// Apply the provider-level default of deletion_protection, also when
+		// the rest of the plan is unchanged
+		var deletionProtection types.Bool
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("deletion_protection"), &deletionProtection)...)
+
+		if deletionProtection.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("deletion_protection"), r.defaultDeletionProtection())...)
+		}
+
+		r.planMetadata(ctx, req, resp)
+		r.warnDuplicateName(ctx, req, resp)
+	}
+
+	// Warn when the project is being destroyed, as this cannot be undone
+	if req.Plan.Raw.IsNull() && !req.State.Raw.IsNull() {
+		var data ProjectResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.AddWarning(
+			"Project Data Will Be Purged",
+			fmt.Sprintf("Destroying project %q (%s) permanently deletes all of its traces, observations, scores and other data. This cannot be undone.",
+				data.Name.ValueString(), data.ID.ValueString()),
+		)
+
+	}
+}
+
+// warnDuplicateName warns when a project is created or renamed while another
+// project already has its name
+func (r *ProjectResource) warnDuplicateName(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+
+	var plan, state ProjectResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() || plan.Name.IsUnknown() || plan.Name.Equal(state.Name) {
+		return
+	}
+
+	matches, err := r.client.FindProjectsByName(ctx, plan.Name.ValueString())
+	if err != nil {
+		tflog.Debug(ctx, "Unable to list projects, skipping duplicate name check", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	var others []Project
+	for _, project := range matches {
+		if project.ID != state.ID.ValueString() {
+			others = append(others, project)
+		}
+	}
+
+	if len(others) == 0 {
+		return
+	}
+
+	if req.State.Raw.IsNull() && plan.AdoptExisting.ValueBool() && len(others) == 1 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("name"),
+			"Existing Project Will Be Adopted",
+			fmt.Sprintf("A project named %q already exists (%s). It will be adopted and updated to match the configuration instead of creating a new project.",
+				plan.Name.ValueString(), others[0].ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("name"),
+		"Duplicate Project Name",
+		fmt.Sprintf("Another project named %q already exists (%s). Langfuse allows duplicate project names, so applying this plan results in several projects with the same name. "+
+			"Set adopt_existing to true or import the existing project to manage it instead.",
+			plan.Name.ValueString(), strings.Join(projectIDs(others), ", ")),
+	)
+}
+
+// projectIDs returns the IDs of the given projects
+func projectIDs(projects []Project) []string {
+	ids := make([]string, len(projects))
+	for i, project := range projects {
+		ids[i] = project.ID
+	}
+
+	return ids
+}
+
+// setRetentionDays updates retention_days from the retention returned by
+// Langfuse, where no retention and 0 both mean that retention is disabled
+func setRetentionDays(data *ProjectResourceModel, retention *int) {
+	switch {
+	case retention != nil && *retention > 0:
+		data.RetentionDays = types.Int64Value(int64(*retention))
+	case data.RetentionDays.IsNull():
+		// Keep the attribute unset
+	default:
+		data.RetentionDays = types.Int64Value(0)
+	}
+}
+
+// defaultDeletionProtection returns the deletion_protection of projects that
+// do not set it
+func (r *ProjectResource) defaultDeletionProtection() bool {
+	if r.client != nil && r.client.Defaults.DeletionProtection != nil {
+		return *r.client.Defaults.DeletionProtection
+	}
+
+	return true
 }
 
 func (r *ProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-} 
\ No newline at end of file
+	// Import formats: <project id>, name:<project name> or
+	// org:<organization id>/name:<project name>
+	orgID, name, byName := parseProjectImportID(req.ID)
+	if !byName {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+
+	if name == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import Identifier",
+			fmt.Sprintf("Expected an import identifier in the format <project id>, name:<project name> or org:<organization id>/name:<project name>, got: %q", req.ID),
+		)
+		return
+	}
+
+	projects, err := r.client.FindProjectsByName(ctx, name)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list projects", err, projectAPIFields)
+		return
+	}
+
+	if orgID != "" {
+		var inOrg []Project
+		verified := false
+		for _, project := range projects {
+			if project.OrganizationID == "" {
+				continue
+			}
+			verified = true
+			if project.OrganizationID == orgID {
+				inOrg = append(inOrg, project)
+			}
+		}
+
+		if verified {
+			projects = inOrg
+		} else {
+			resp.Diagnostics.AddWarning(
+				"Organization Not Verified",
+				fmt.Sprintf("Langfuse did not report the organization of the listed projects, so the organization %q could not be verified. "+
+					"Projects are looked up in the organization of the configured API key.", orgID),
+			)
+		}
+	}
+
+	switch len(projects) {
+	case 0:
+		resp.Diagnostics.AddError(
+			"Project Not Found",
+			fmt.Sprintf("No project named %q exists in the organization of the configured API key.", name),
+		)
+		return
+	case 1:
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), projects[0].ID)...)
+	default:
+		resp.Diagnostics.AddError(
+			"Ambiguous Project Name",
+			fmt.Sprintf("%d projects are named %q, import one of them by ID instead: %s", len(projects), name, strings.Join(projectIDs(projects), ", ")),
+		)
+		return
+	}
+}
+
+// parseProjectImportID splits a project import identifier. byName is false
+// when the identifier is a plain project ID.
+func parseProjectImportID(importID string) (orgID, name string, byName bool) {
+	if rest, ok := strings.CutPrefix(importID, "org:"); ok {
+		orgID, rest, ok = strings.Cut(rest, "/")
+		if !ok || orgID == "" {
+			return "", "", true
+		}
+		name, ok = strings.CutPrefix(rest, "name:")
+		if !ok {
+			return "", "", true
+		}
+		return orgID, name, true
+	}
+
+	if name, ok := strings.CutPrefix(importID, "name:"); ok {
+		return "", name, true
+	}
+
+	return "", "", false
+}
+
+// projectResourceModelV0 describes the resource data model before
+// metadata_json was added.
+type projectResourceModelV0 struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Metadata      types.Map    `tfsdk:"metadata"`
+	RetentionDays types.Int64  `tfsdk:"retention_days"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	UpdatedAt     types.String `tfsdk:"updated_at"`
+
+	WaitForDeletion types.Bool `tfsdk:"wait_for_deletion"`
+	AllowSelfDelete types.Bool `tfsdk:"allow_self_delete"`
+
+	DeletionProtection types.Bool `tfsdk:"deletion_protection"`
+	AdoptExisting      types.Bool `tfsdk:"adopt_existing"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *ProjectResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 only stored string metadata in the metadata map
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                  schema.StringAttribute{Computed: true},
+					"name":                schema.StringAttribute{Required: true},
+					"metadata":            schema.MapAttribute{ElementType: types.StringType, Optional: true},
+					"retention_days":      schema.Int64Attribute{Optional: true},
+					"created_at":          schema.StringAttribute{Computed: true},
+					"updated_at":          schema.StringAttribute{Computed: true},
+					"wait_for_deletion":   schema.BoolAttribute{Optional: true, Computed: true},
+					"allow_self_delete":   schema.BoolAttribute{Optional: true},
+					"deletion_protection": schema.BoolAttribute{Optional: true, Computed: true},
+					"adopt_existing":      schema.BoolAttribute{Optional: true},
+				},
+				Blocks: map[string]schema.Block{
+					"timeouts": timeouts.Block(ctx, timeouts.Opts{
+						Create: true,
+						Read:   true,
+						Update: true,
+						Delete: true,
+					}),
+				},
+			},
+			StateUpgrader: upgradeProjectStateV0,
+		},
+	}
+}
+
+// upgradeProjectStateV0 migrates the metadata map of a version 0 state to
+// metadata_json
+func upgradeProjectStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior projectResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data := ProjectResourceModel{
+		ID:                 prior.ID,
+		Name:               prior.Name,
+		Metadata:           prior.Metadata,
+		RetentionDays:      prior.RetentionDays,
+		CreatedAt:          prior.CreatedAt,
+		UpdatedAt:          prior.UpdatedAt,
+		MetadataJSON:       jsontypes.NewNormalizedNull(),
+		MetadataAll:        types.MapNull(types.StringType),
+		WaitForDeletion:    prior.WaitForDeletion,
+		DeletionProtection: prior.DeletionProtection,
+		AdoptExisting:      prior.AdoptExisting,
+		Timeouts:           prior.Timeouts,
+	}
+
+	if !prior.Metadata.IsNull() {
+		metadata := make(map[string]interface{}, len(prior.Metadata.Elements()))
+		for key, value := range prior.Metadata.Elements() {
+			if strValue, ok := value.(types.String); ok {
+				metadata[key] = strValue.ValueString()
+			}
+		}
+
+		encoded, err := encodeMetadata(metadata)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Upgrade Project State", err.Error())
+			return
+		}
+		data.MetadataJSON = encoded
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
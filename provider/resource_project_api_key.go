@@ -3,11 +3,19 @@ package provider
 import (
 	"context"
 	"fmt"
-
+	"strings"
+	"time"
+
+	"github.com/cirobaronneto/terraform-provider-langfuse/internal/validators"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -15,6 +23,11 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ProjectApiKeyResource{}
 var _ resource.ResourceWithImportState = &ProjectApiKeyResource{}
+var _ resource.ResourceWithModifyPlan = &ProjectApiKeyResource{}
+var _ resource.ResourceWithConfigValidators = &ProjectApiKeyResource{}
+
+// timeNow returns the current time, and is replaced in tests
+var timeNow = time.Now
 
 func NewProjectApiKeyResource() resource.Resource {
 	return &ProjectApiKeyResource{}
@@ -34,6 +47,26 @@ type ProjectApiKeyResourceModel struct {
 	SecretKey        types.String `tfsdk:"secret_key"`
 	DisplaySecretKey types.String `tfsdk:"display_secret_key"`
 	CreatedAt        types.String `tfsdk:"created_at"`
+
+	RotationDays         types.Int64  `tfsdk:"rotation_days"`
+	RotationGraceDays    types.Int64  `tfsdk:"rotation_grace_days"`
+	Keepers              types.Map    `tfsdk:"keepers"`
+	PreviousKeyID        types.String `tfsdk:"previous_key_id"`
+	PreviousPublicKey    types.String `tfsdk:"previous_public_key"`
+	PreviousKeyExpiresAt types.String `tfsdk:"previous_key_expires_at"`
+
+	PGPKey             types.String `tfsdk:"pgp_key"`
+	EncryptedSecretKey types.String `tfsdk:"encrypted_secret_key"`
+	KeyFingerprint     types.String `tfsdk:"key_fingerprint"`
+
+	AllowSelfDelete types.Bool `tfsdk:"allow_self_delete"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// apiKeyAPIFields maps Langfuse API request fields to resource attributes
+var apiKeyAPIFields = map[string]path.Path{
+	"note": path.Root("note"),
 }
 
 func (r *ProjectApiKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -59,35 +92,163 @@ func (r *ProjectApiKeyResource) Schema(ctx context.Context, req resource.SchemaR
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					validators.ID(),
+				},
 			},
 			"note": schema.StringAttribute{
-				MarkdownDescription: "Optional note for the API key",
+				MarkdownDescription: "Optional note for the API key, with at most 1000 characters",
 				Optional:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					validators.Note(),
+				},
 			},
 			"public_key": schema.StringAttribute{
 				MarkdownDescription: "Public key for the API key",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"secret_key": schema.StringAttribute{
-				MarkdownDescription: "Secret key for the API key (only available on creation)",
+				MarkdownDescription: "Secret key for the API key (only available on creation, null for imported keys and when `pgp_key` is set)",
 				Computed:            true,
 				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"display_secret_key": schema.StringAttribute{
 				MarkdownDescription: "Display version of the secret key",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"created_at": schema.StringAttribute{
 				MarkdownDescription: "Timestamp when the API key was created",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
+			"pgp_key": schema.StringAttribute{
+				MarkdownDescription: "PGP public key, ASCII armored or base64 encoded, used to encrypt the secret key. When set, the secret key is only stored encrypted in `encrypted_secret_key` and `secret_key` is null.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"encrypted_secret_key": schema.StringAttribute{
+				MarkdownDescription: "Base64 encoded secret key encrypted with `pgp_key`. Decrypt it with `base64 --decode | gpg --decrypt`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_fingerprint": schema.StringAttribute{
+				MarkdownDescription: "Fingerprint of the PGP key used to encrypt the secret key",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"allow_self_delete": schema.BoolAttribute{
+				MarkdownDescription: "Allow destroying, replacing or rotating this key when it is the key the provider is configured with. Such plans fail by default, as they would lock Terraform out of Langfuse during the apply.",
+				Optional:            true,
+			},
+			"rotation_days": schema.Int64Attribute{
+				MarkdownDescription: "Rotate the API key once it is older than this many days, based on `created_at`. The new key is created before the old one is deleted.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"rotation_grace_days": schema.Int64Attribute{
+				MarkdownDescription: "Keep the previous key valid for this many days after a rotation. It is deleted by the first apply after the grace period ends. Requires `rotation_days` or `keepers` and must be less than `rotation_days`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that rotate the API key when they change, in the same way as `rotation_days`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"previous_key_id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the previous API key while it is kept valid after a rotation",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"previous_public_key": schema.StringAttribute{
+				MarkdownDescription: "Public key of the previous API key while it is kept valid after a rotation",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"previous_key_expires_at": schema.StringAttribute{
+				MarkdownDescription: "Timestamp after which the previous API key is deleted by the next apply",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+func (r *ProjectApiKeyResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		// A grace period only applies to rotations, and a further rotation
+		// would delete the previous key before it ends
+		validators.AlsoRequiresOneOf(path.Root("rotation_grace_days"), path.Root("rotation_days"), path.Root("keepers")),
+		validators.Int64LessThan(path.Root("rotation_grace_days"), path.Root("rotation_days")),
+	}
+}
+
+// setSecretKey stores the secret of a newly created key in the model. When
+// pgp_key is set, only the encrypted secret is stored.
+func setSecretKey(data *ProjectApiKeyResourceModel, secret string) error {
+	data.SecretKey = types.StringNull()
+	data.EncryptedSecretKey = types.StringNull()
+	data.KeyFingerprint = types.StringNull()
+
+	if secret == "" {
+		return nil
+	}
+
+	if data.PGPKey.IsNull() {
+		data.SecretKey = types.StringValue(secret)
+		return nil
+	}
+
+	encrypted, fingerprint, err := encryptSecret(data.PGPKey.ValueString(), secret)
+	if err != nil {
+		return err
+	}
+
+	data.EncryptedSecretKey = types.StringValue(encrypted)
+	data.KeyFingerprint = types.StringValue(fingerprint)
+
+	return nil
+}
+
 func (r *ProjectApiKeyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -118,6 +279,24 @@ func (r *ProjectApiKeyResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// Check the PGP key before creating a key whose secret cannot be stored
+	if !data.PGPKey.IsNull() {
+		if _, err := readPGPPublicKey(data.PGPKey.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "Invalid PGP Key", err.Error())
+			return
+		}
+	}
+
 	// Create API request
 	createReq := CreateApiKeyRequest{}
 
@@ -128,9 +307,9 @@ func (r *ProjectApiKeyResource) Create(ctx context.Context, req resource.CreateR
 	}
 
 	// Create API key
-	apiKey, err := r.client.CreateApiKey(data.ProjectID.ValueString(), createReq)
+	apiKey, err := r.client.CreateApiKey(ctx, data.ProjectID.ValueString(), createReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create API key, got error: %s", err))
+		addClientError(&resp.Diagnostics, "create API key", err, apiKeyAPIFields)
 		return
 	}
 
@@ -140,8 +319,8 @@ func (r *ProjectApiKeyResource) Create(ctx context.Context, req resource.CreateR
 	data.CreatedAt = types.StringValue(apiKey.CreatedAt)
 
 	// Set the secret key (only available on creation)
-	if apiKey.SecretKey != "" {
-		data.SecretKey = types.StringValue(apiKey.SecretKey)
+	if err := setSecretKey(&data, apiKey.SecretKey); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "Unable to Encrypt Secret Key", err.Error())
 	}
 
 	// Set display secret key
@@ -154,6 +333,10 @@ func (r *ProjectApiKeyResource) Create(ctx context.Context, req resource.CreateR
 		data.Note = types.StringValue(*apiKey.Note)
 	}
 
+	data.PreviousKeyID = types.StringNull()
+	data.PreviousPublicKey = types.StringNull()
+	data.PreviousKeyExpiresAt = types.StringNull()
+
 	// Write logs using the tflog package
 	tflog.Trace(ctx, "created an API key resource")
 
@@ -171,10 +354,25 @@ func (r *ProjectApiKeyResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	// Get API key from API
-	apiKey, err := r.client.GetApiKey(data.ProjectID.ValueString(), data.ID.ValueString())
+	apiKey, err := r.client.GetApiKey(ctx, data.ProjectID.ValueString(), data.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read API key, got error: %s", err))
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "API key no longer exists, removing from state", map[string]interface{}{"id": data.ID.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		addClientError(&resp.Diagnostics, "read API key", err, apiKeyAPIFields)
 		return
 	}
 
@@ -200,12 +398,102 @@ func (r *ProjectApiKeyResource) Read(ctx context.Context, req resource.ReadReque
 }
 
 func (r *ProjectApiKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// API keys are immutable in Langfuse API - any change requires replacement
-	// This is handled by the RequiresReplace plan modifiers in the schema
-	resp.Diagnostics.AddError(
-		"Update Not Supported",
-		"API keys cannot be updated. Any changes require replacement of the resource.",
-	)
+	// API keys are immutable in Langfuse API - changes to the key itself
+	// require replacement, which is handled by the RequiresReplace plan
+	// modifiers in the schema. Update rotates the key when ModifyPlan
+	// scheduled a rotation and otherwise only records Terraform-side settings.
+	var data, state ProjectApiKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	projectID := data.ProjectID.ValueString()
+
+	if !data.ID.IsUnknown() {
+		// The grace period of the previous key ended
+		if data.PreviousKeyID.IsNull() && !state.PreviousKeyID.IsNull() {
+			err := r.client.DeleteApiKey(ctx, projectID, state.PreviousKeyID.ValueString())
+			if err != nil && !IsNotFound(err) {
+				addClientError(&resp.Diagnostics, "delete previous API key", err, apiKeyAPIFields)
+				return
+			}
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if !data.PGPKey.IsNull() {
+		if _, err := readPGPPublicKey(data.PGPKey.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "Invalid PGP Key", err.Error())
+			return
+		}
+	}
+
+	// Rotate the key: create the new key before deleting the old one
+	createReq := CreateApiKeyRequest{}
+	if !data.Note.IsNull() && !data.Note.IsUnknown() {
+		note := data.Note.ValueString()
+		createReq.Note = &note
+	}
+
+	apiKey, err := r.client.CreateApiKey(ctx, projectID, createReq)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "rotate API key", err, apiKeyAPIFields)
+		return
+	}
+
+	data.ID = types.StringValue(apiKey.ID)
+	data.PublicKey = types.StringValue(apiKey.PublicKey)
+	data.DisplaySecretKey = types.StringValue(apiKey.DisplaySecretKey)
+	data.CreatedAt = types.StringValue(apiKey.CreatedAt)
+
+	if err := setSecretKey(&data, apiKey.SecretKey); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "Unable to Encrypt Secret Key", err.Error())
+	}
+
+	// Only one previous key is kept at a time
+	if !state.PreviousKeyID.IsNull() {
+		err := r.client.DeleteApiKey(ctx, projectID, state.PreviousKeyID.ValueString())
+		if err != nil && !IsNotFound(err) {
+			addClientError(&resp.Diagnostics, "delete previous API key", err, apiKeyAPIFields)
+		}
+	}
+
+	graceDays := data.RotationGraceDays.ValueInt64()
+	if graceDays > 0 {
+		data.PreviousKeyID = state.ID
+		data.PreviousPublicKey = state.PublicKey
+		data.PreviousKeyExpiresAt = types.StringValue(timeNow().UTC().Add(time.Duration(graceDays) * 24 * time.Hour).Format(time.RFC3339))
+	} else {
+		data.PreviousKeyID = types.StringNull()
+		data.PreviousPublicKey = types.StringNull()
+		data.PreviousKeyExpiresAt = types.StringNull()
+
+		err := r.client.DeleteApiKey(ctx, projectID, state.ID.ValueString())
+		if err != nil && !IsNotFound(err) {
+			addClientError(&resp.Diagnostics, "delete rotated API key", err, apiKeyAPIFields)
+		}
+	}
+
+	tflog.Trace(ctx, "rotated an API key resource")
+
+	// Save the new key even when deleting an old key failed, so it is not lost
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ProjectApiKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -218,23 +506,220 @@ func (r *ProjectApiKeyResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	// Delete API key
-	err := r.client.DeleteApiKey(data.ProjectID.ValueString(), data.ID.ValueString())
+	err := r.client.DeleteApiKey(ctx, data.ProjectID.ValueString(), data.ID.ValueString())
+	if err != nil && !IsNotFound(err) {
+		addClientError(&resp.Diagnostics, "delete API key", err, apiKeyAPIFields)
+		return
+	}
+
+	// Delete the previous key still in its rotation grace period
+	if !data.PreviousKeyID.IsNull() {
+		err := r.client.DeleteApiKey(ctx, data.ProjectID.ValueString(), data.PreviousKeyID.ValueString())
+		if err != nil && !IsNotFound(err) {
+			addClientError(&resp.Diagnostics, "delete previous API key", err, apiKeyAPIFields)
+			return
+		}
+	}
+}
+
+func (r *ProjectApiKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is deleted when a key is created
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state ProjectApiKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		r.checkSelfDelete(ctx, &resp.Diagnostics, state.AllowSelfDelete, "destroy the API key resource", state.PublicKey, state.PreviousPublicKey)
+		return
+	}
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keys that are replaced anyway do not need to be rotated
+	if plan.ID.IsUnknown() || apiKeyRequiresReplace(plan, state) {
+		r.checkSelfDelete(ctx, &resp.Diagnostics, plan.AllowSelfDelete, "replace the API key resource", state.PublicKey, state.PreviousPublicKey)
+		return
+	}
+
+	now := timeNow()
+	reason := ""
+
+	if !plan.Keepers.IsUnknown() && !plan.Keepers.Equal(state.Keepers) {
+		reason = "its keepers changed"
+	} else if !plan.RotationDays.IsNull() && !plan.RotationDays.IsUnknown() {
+		createdAt, err := time.Parse(time.RFC3339, state.CreatedAt.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("rotation_days"),
+				"Unable to Determine API Key Age",
+				fmt.Sprintf("The API key will not be rotated, as its creation time %q could not be parsed: %s", state.CreatedAt.ValueString(), err),
+			)
+		} else if rotateAt := createdAt.Add(time.Duration(plan.RotationDays.ValueInt64()) * 24 * time.Hour); !now.Before(rotateAt) {
+			reason = fmt.Sprintf("it is older than %d days", plan.RotationDays.ValueInt64())
+		}
+	}
+
+	if reason != "" {
+		// Update creates the new key and retires the old one
+		plan.ID = types.StringUnknown()
+		plan.PublicKey = types.StringUnknown()
+		plan.SecretKey = types.StringUnknown()
+		plan.EncryptedSecretKey = types.StringUnknown()
+		plan.KeyFingerprint = types.StringUnknown()
+		plan.DisplaySecretKey = types.StringUnknown()
+		plan.CreatedAt = types.StringUnknown()
+
+		if plan.RotationGraceDays.ValueInt64() > 0 {
+			plan.PreviousKeyID = state.ID
+			plan.PreviousPublicKey = state.PublicKey
+			plan.PreviousKeyExpiresAt = types.StringUnknown()
+		} else {
+			plan.PreviousKeyID = types.StringNull()
+			plan.PreviousPublicKey = types.StringNull()
+			plan.PreviousKeyExpiresAt = types.StringNull()
+		}
+
+		// Rotation deletes the previous key, and the current key unless it
+		// gets a grace period
+		retired := []types.String{state.PreviousPublicKey}
+		if plan.RotationGraceDays.ValueInt64() <= 0 {
+			retired = append(retired, state.PublicKey)
+		}
+		r.checkSelfDelete(ctx, &resp.Diagnostics, plan.AllowSelfDelete, "rotate the API key", retired...)
+
+		detail := fmt.Sprintf("The API key %s will be replaced by a new key because %s. ", state.PublicKey.ValueString(), reason)
+		if graceDays := plan.RotationGraceDays.ValueInt64(); graceDays > 0 {
+			detail += fmt.Sprintf("The current key stays valid for %d days after the rotation and is deleted by the first apply after that.", graceDays)
+		} else {
+			detail += "The current key is deleted once the new key has been created, so applications using it must be updated."
+		}
+
+		resp.Diagnostics.AddWarning("API Key Will Be Rotated", detail)
+	} else {
+		// The key is kept, and so is its previous key until its grace period
+		// ends. The state values are copied, as the framework plans computed
+		// attributes that are null in state as unknown, such as the secret of
+		// imported keys and the encrypted secret of keys without pgp_key.
+		plan.ID = state.ID
+		plan.PublicKey = state.PublicKey
+		plan.SecretKey = state.SecretKey
+		plan.EncryptedSecretKey = state.EncryptedSecretKey
+		plan.KeyFingerprint = state.KeyFingerprint
+		plan.DisplaySecretKey = state.DisplaySecretKey
+		plan.CreatedAt = state.CreatedAt
+		plan.PreviousKeyID = state.PreviousKeyID
+		plan.PreviousPublicKey = state.PreviousPublicKey
+		plan.PreviousKeyExpiresAt = state.PreviousKeyExpiresAt
+
+		if !state.PreviousKeyExpiresAt.IsNull() {
+			expiresAt, err := time.Parse(time.RFC3339, state.PreviousKeyExpiresAt.ValueString())
+			if err == nil && !now.Before(expiresAt) {
+				r.checkSelfDelete(ctx, &resp.Diagnostics, plan.AllowSelfDelete, "delete the previous API key after its grace period", state.PreviousPublicKey)
+
+				plan.PreviousKeyID = types.StringNull()
+				plan.PreviousPublicKey = types.StringNull()
+				plan.PreviousKeyExpiresAt = types.StringNull()
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// apiKeyRequiresReplace reports whether the plan changes an attribute that
+// forces a new key
+func apiKeyRequiresReplace(plan, state ProjectApiKeyResourceModel) bool {
+	return !plan.ProjectID.Equal(state.ProjectID) || !plan.Note.Equal(state.Note) || !plan.PGPKey.Equal(state.PGPKey)
+}
+
+// checkSelfDelete fails the plan when one of the keys it deletes is the key
+// the provider currently authenticates with, unless allow_self_delete is set
+func (r *ProjectApiKeyResource) checkSelfDelete(ctx context.Context, diags *diag.Diagnostics, allow types.Bool, action string, publicKeys ...types.String) {
+	if r.client == nil || allow.ValueBool() {
+		return
+	}
+
+	// Keys from a credential process may have been refreshed since Configure
+	currentKey, _, err := r.client.credentials(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete API key, got error: %s", err))
+		diags.AddError("Unable to Determine Provider Credentials", err.Error())
 		return
 	}
+	if currentKey == "" {
+		return
+	}
+
+	for _, publicKey := range publicKeys {
+		if publicKey.ValueString() == currentKey {
+			addSelfDeleteError(diags, publicKey.ValueString(), action)
+			return
+		}
+	}
 }
 
 func (r *ProjectApiKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: project_id:api_key_id
-	// This allows importing using both the project ID and API key ID
-	// Example: terraform import langfuse_project_api_key.example project-123:api-key-456
-	
-	// For now, we'll use a simple ID format and expect users to set project_id separately
-	// This could be enhanced to parse "project_id:api_key_id" format if needed
-	resp.Diagnostics.AddError(
-		"Import Not Implemented",
-		"Import is not yet implemented for API key resources. Please recreate the resource instead.",
+	// Import format: project_id:api_key_id or project_id:public_key
+	// Example: terraform import langfuse_project_api_key.example project-123:pk-lf-...
+	projectID, keyRef, ok := strings.Cut(req.ID, ":")
+	if !ok || projectID == "" || keyRef == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import Identifier",
+			fmt.Sprintf("Expected an import identifier in the format project_id:api_key_id or project_id:public_key, got: %q", req.ID),
+		)
+		return
+	}
+
+	apiKeys, err := r.client.ListApiKeys(ctx, projectID)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list API keys", err, apiKeyAPIFields)
+		return
+	}
+
+	var apiKey *ApiKey
+	for i := range apiKeys {
+		if apiKeys[i].ID == keyRef || apiKeys[i].PublicKey == keyRef {
+			apiKey = &apiKeys[i]
+			break
+		}
+	}
+
+	if apiKey == nil {
+		resp.Diagnostics.AddError(
+			"API Key Not Found",
+			fmt.Sprintf("No API key with ID or public key %q exists in project %s.", keyRef, projectID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), apiKey.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectID)...)
+
+	resp.Diagnostics.AddWarning(
+		"Secret Key Not Imported",
+		fmt.Sprintf("Langfuse only returns the secret key of an API key when it is created, so secret_key of the imported key %s is null. "+
+			"The key itself keeps working. Replace the resource if Terraform needs to manage the secret key.", apiKey.PublicKey),
 	)
-} 
\ No newline at end of file
+}
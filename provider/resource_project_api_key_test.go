@@ -0,0 +1,391 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProjectApiKeyResourceImportState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/projects/project-1/apiKeys" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"apiKeys":[{"id":"key-1","publicKey":"pk-lf-1","displaySecretKey":"sk-lf-...abcd","createdAt":"2024-01-01T00:00:00.000Z"}]}`)
+	}))
+	defer server.Close()
+
+	r, s := newTestApiKeyResource(t, server.URL)
+
+	for _, importID := range []string{"project-1:key-1", "project-1:pk-lf-1"} {
+		resp := resource.ImportStateResponse{State: emptyState(s)}
+		r.ImportState(context.Background(), resource.ImportStateRequest{ID: importID}, &resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected error: %v", importID, resp.Diagnostics)
+		}
+		if resp.Diagnostics.WarningsCount() != 1 {
+			t.Errorf("%s: expected a secret key warning, got: %v", importID, resp.Diagnostics)
+		}
+
+		var id, projectID, secretKey types.String
+		resp.State.GetAttribute(context.Background(), tfpath.Root("id"), &id)
+		resp.State.GetAttribute(context.Background(), tfpath.Root("project_id"), &projectID)
+		resp.State.GetAttribute(context.Background(), tfpath.Root("secret_key"), &secretKey)
+
+		if id.ValueString() != "key-1" || projectID.ValueString() != "project-1" {
+			t.Errorf("%s: unexpected state: id=%s project_id=%s", importID, id, projectID)
+		}
+		if !secretKey.IsNull() {
+			t.Errorf("%s: expected secret_key to be null, got %s", importID, secretKey)
+		}
+	}
+
+	for _, importID := range []string{"key-1", "project-1:", ":key-1", "project-1:missing", "project-2:key-1"} {
+		resp := resource.ImportStateResponse{State: emptyState(s)}
+		r.ImportState(context.Background(), resource.ImportStateRequest{ID: importID}, &resp)
+
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("%s: expected an error", importID)
+		}
+	}
+}
+
+// fakeApiKeyServer serves the API key endpoints of a single project and
+// records created and deleted keys
+type fakeApiKeyServer struct {
+	*httptest.Server
+	created []string
+	deleted []string
+}
+
+func newFakeApiKeyServer(t *testing.T) *fakeApiKeyServer {
+	t.Helper()
+
+	f := &fakeApiKeyServer{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			id := fmt.Sprintf("key-new-%d", len(f.created)+1)
+			f.created = append(f.created, id)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":%q,"publicKey":"pk-lf-%s","secretKey":"sk-lf-%s","displaySecretKey":"sk-lf-...","createdAt":"2024-06-01T00:00:00.000Z"}`, id, id, id)
+		case http.MethodDelete:
+			f.deleted = append(f.deleted, path.Base(r.URL.Path))
+			fmt.Fprint(w, `{"success":true}`)
+		default:
+			fmt.Fprint(w, `{"apiKeys":[]}`)
+		}
+	}))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// testApiKeyModel returns the state of an existing API key created at the
+// given time
+func testApiKeyModel(t *testing.T, s schema.Schema, createdAt time.Time) ProjectApiKeyResourceModel {
+	t.Helper()
+
+	return ProjectApiKeyResourceModel{
+		ID:                   types.StringValue("key-old"),
+		ProjectID:            types.StringValue("project-1"),
+		Note:                 types.StringNull(),
+		PublicKey:            types.StringValue("pk-lf-old"),
+		SecretKey:            types.StringValue("sk-lf-old"),
+		DisplaySecretKey:     types.StringValue("sk-lf-...old"),
+		CreatedAt:            types.StringValue(createdAt.UTC().Format(time.RFC3339)),
+		RotationDays:         types.Int64Value(90),
+		RotationGraceDays:    types.Int64Null(),
+		Keepers:              types.MapNull(types.StringType),
+		PreviousKeyID:        types.StringNull(),
+		PreviousPublicKey:    types.StringNull(),
+		PreviousKeyExpiresAt: types.StringNull(),
+		PGPKey:               types.StringNull(),
+		EncryptedSecretKey:   types.StringNull(),
+		KeyFingerprint:       types.StringNull(),
+		AllowSelfDelete:      types.BoolNull(),
+		Timeouts:             nullTimeouts(t, s),
+	}
+}
+
+// modifyApiKeyPlan runs ModifyPlan for an unchanged configuration
+func modifyApiKeyPlan(t *testing.T, r *ProjectApiKeyResource, s schema.Schema, state, plan ProjectApiKeyResourceModel) (ProjectApiKeyResourceModel, resource.ModifyPlanResponse) {
+	t.Helper()
+
+	req := resource.ModifyPlanRequest{
+		State: stateFromModel(t, s, &state),
+		Plan:  planFromModel(t, s, &plan),
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(context.Background(), req, &resp)
+
+	var planned ProjectApiKeyResourceModel
+	resp.Plan.Get(context.Background(), &planned)
+
+	return planned, resp
+}
+
+// updateApiKey runs Update from state to plan and returns the new state
+func updateApiKey(t *testing.T, r *ProjectApiKeyResource, s schema.Schema, state, plan ProjectApiKeyResourceModel) ProjectApiKeyResourceModel {
+	t.Helper()
+
+	req := resource.UpdateRequest{
+		State: stateFromModel(t, s, &state),
+		Plan:  planFromModel(t, s, &plan),
+	}
+	resp := resource.UpdateResponse{State: req.State}
+	r.Update(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected update error: %v", resp.Diagnostics)
+	}
+
+	var updated ProjectApiKeyResourceModel
+	resp.State.Get(context.Background(), &updated)
+
+	return updated
+}
+
+func TestProjectApiKeyResourceUpdateTimeouts(t *testing.T) {
+	fake := newFakeApiKeyServer(t)
+	server := configuredProviderServer(t, fake.URL)
+	_, s := newTestApiKeyResource(t, fake.URL)
+
+	state := testApiKeyModel(t, s, timeNow())
+	state.RotationDays = types.Int64Null()
+
+	// Only the timeouts change, which is applied without touching the key
+	timeoutsType := s.Type().TerraformType(context.Background()).(tftypes.Object).AttributeTypes["timeouts"]
+	newState := applyResourceChange(t, server, "langfuse_project_api_key", s, stateFromModel(t, s, &state), map[string]tftypes.Value{
+		"timeouts": tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+			"create": tftypes.NewValue(tftypes.String, "10m"),
+			"read":   tftypes.NewValue(tftypes.String, nil),
+			"update": tftypes.NewValue(tftypes.String, "5m"),
+			"delete": tftypes.NewValue(tftypes.String, nil),
+		}),
+	})
+	if !newState.IsFullyKnown() {
+		t.Fatalf("expected the new state to be known, got %s", newState)
+	}
+
+	var updated ProjectApiKeyResourceModel
+	if diags := (tfsdk.State{Schema: s, Raw: newState}).Get(context.Background(), &updated); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	updateTimeout, diags := updated.Timeouts.Update(context.Background(), defaultUpdateTimeout)
+	if diags.HasError() || updateTimeout != 5*time.Minute {
+		t.Errorf("expected the planned timeouts to be saved, got %s", updated.Timeouts)
+	}
+	if updated.ID.ValueString() != "key-old" || updated.SecretKey.ValueString() != "sk-lf-old" {
+		t.Errorf("expected the key to be kept, got %s", updated.ID)
+	}
+	if len(fake.created) != 0 || len(fake.deleted) != 0 {
+		t.Errorf("expected no API key changes, got created %v and deleted %v", fake.created, fake.deleted)
+	}
+}
+
+func TestProjectApiKeyResourceInPlaceUpdate(t *testing.T) {
+	fake := newFakeApiKeyServer(t)
+	server := configuredProviderServer(t, fake.URL)
+	_, s := newTestApiKeyResource(t, fake.URL)
+
+	state := testApiKeyModel(t, s, timeNow())
+	state.RotationDays = types.Int64Null()
+
+	// Imported keys have no secret
+	imported := state
+	imported.SecretKey = types.StringNull()
+
+	for name, test := range map[string]struct {
+		state   ProjectApiKeyResourceModel
+		changes map[string]tftypes.Value
+	}{
+		"rotation_days added":       {state, map[string]tftypes.Value{"rotation_days": tftypes.NewValue(tftypes.Number, 90)}},
+		"allow_self_delete toggled": {state, map[string]tftypes.Value{"allow_self_delete": tftypes.NewValue(tftypes.Bool, true)}},
+		"imported key":              {imported, map[string]tftypes.Value{"rotation_days": tftypes.NewValue(tftypes.Number, 90)}},
+	} {
+		newState := applyResourceChange(t, server, "langfuse_project_api_key", s, stateFromModel(t, s, &test.state), test.changes)
+		if !newState.IsFullyKnown() {
+			t.Errorf("%s: expected the new state to be known, got %s", name, newState)
+		}
+
+		var updated ProjectApiKeyResourceModel
+		if diags := (tfsdk.State{Schema: s, Raw: newState}).Get(context.Background(), &updated); diags.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", name, diags)
+		}
+		if updated.ID.ValueString() != "key-old" {
+			t.Errorf("%s: expected the key to be kept, got %s", name, updated.ID)
+		}
+		if !updated.PreviousKeyID.IsNull() || !updated.PreviousPublicKey.IsNull() || !updated.PreviousKeyExpiresAt.IsNull() {
+			t.Errorf("%s: expected no previous key, got %s %s %s", name, updated.PreviousKeyID, updated.PreviousPublicKey, updated.PreviousKeyExpiresAt)
+		}
+		if !updated.SecretKey.Equal(test.state.SecretKey) || !updated.EncryptedSecretKey.IsNull() || !updated.KeyFingerprint.IsNull() {
+			t.Errorf("%s: expected the secret attributes to be kept, got %s %s %s", name, updated.SecretKey, updated.EncryptedSecretKey, updated.KeyFingerprint)
+		}
+	}
+
+	if len(fake.created) != 0 || len(fake.deleted) != 0 {
+		t.Errorf("expected no API key changes, got created %v and deleted %v", fake.created, fake.deleted)
+	}
+}
+
+func TestProjectApiKeyResourceRotation(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	previousNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = previousNow }()
+
+	server := newFakeApiKeyServer(t)
+	r, s := newTestApiKeyResource(t, server.URL)
+
+	// A recent key is left alone
+	state := testApiKeyModel(t, s, now.AddDate(0, 0, -10))
+	planned, resp := modifyApiKeyPlan(t, r, s, state, state)
+	if resp.Diagnostics.HasError() || planned.ID.IsUnknown() {
+		t.Fatalf("expected no rotation for a recent key, got %v", resp.Diagnostics)
+	}
+
+	// An old key is rotated and deleted once the new key exists
+	state = testApiKeyModel(t, s, now.AddDate(0, 0, -91))
+	planned, resp = modifyApiKeyPlan(t, r, s, state, state)
+	if !planned.ID.IsUnknown() || !planned.SecretKey.IsUnknown() {
+		t.Fatalf("expected rotation to be planned, got id=%s", planned.ID)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a rotation warning, got %v", resp.Diagnostics)
+	}
+
+	updated := updateApiKey(t, r, s, state, planned)
+	if updated.ID.ValueString() != "key-new-1" || updated.SecretKey.ValueString() != "sk-lf-key-new-1" {
+		t.Errorf("unexpected rotated key: %s", updated.ID)
+	}
+	if !updated.PreviousKeyID.IsNull() {
+		t.Errorf("expected no previous key without a grace period, got %s", updated.PreviousKeyID)
+	}
+	if len(server.deleted) != 1 || server.deleted[0] != "key-old" {
+		t.Errorf("expected old key to be deleted, got %v", server.deleted)
+	}
+}
+
+func TestProjectApiKeyResourceRotationGracePeriod(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	previousNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = previousNow }()
+
+	server := newFakeApiKeyServer(t)
+	r, s := newTestApiKeyResource(t, server.URL)
+
+	// Changing keepers rotates the key, keeping the old key during the grace period
+	state := testApiKeyModel(t, s, now.AddDate(0, 0, -10))
+	state.RotationGraceDays = types.Int64Value(7)
+	plan := state
+	plan.Keepers = types.MapValueMust(types.StringType, map[string]attr.Value{"version": types.StringValue("2")})
+
+	planned, _ := modifyApiKeyPlan(t, r, s, state, plan)
+	if !planned.ID.IsUnknown() || planned.PreviousKeyID.ValueString() != "key-old" {
+		t.Fatalf("expected rotation keeping the old key, got id=%s previous=%s", planned.ID, planned.PreviousKeyID)
+	}
+
+	rotated := updateApiKey(t, r, s, state, planned)
+	if rotated.PreviousKeyID.ValueString() != "key-old" || rotated.PreviousPublicKey.ValueString() != "pk-lf-old" {
+		t.Errorf("expected previous key to be recorded, got %s", rotated.PreviousKeyID)
+	}
+	if rotated.PreviousKeyExpiresAt.ValueString() != "2024-06-08T00:00:00Z" {
+		t.Errorf("unexpected previous key expiry: %s", rotated.PreviousKeyExpiresAt)
+	}
+	if len(server.deleted) != 0 {
+		t.Errorf("expected old key to be kept, got deletions %v", server.deleted)
+	}
+
+	// Within the grace period nothing changes
+	planned, _ = modifyApiKeyPlan(t, r, s, rotated, rotated)
+	if planned.PreviousKeyID.IsNull() {
+		t.Error("expected previous key to be kept during the grace period")
+	}
+
+	// After the grace period the previous key is deleted
+	now = now.AddDate(0, 0, 8)
+	planned, _ = modifyApiKeyPlan(t, r, s, rotated, rotated)
+	if !planned.PreviousKeyID.IsNull() || planned.ID.IsUnknown() {
+		t.Fatalf("expected previous key removal to be planned, got id=%s previous=%s", planned.ID, planned.PreviousKeyID)
+	}
+
+	final := updateApiKey(t, r, s, rotated, planned)
+	if final.ID.ValueString() != "key-new-1" || !final.PreviousKeyID.IsNull() {
+		t.Errorf("unexpected state after grace period: id=%s previous=%s", final.ID, final.PreviousKeyID)
+	}
+	if len(server.deleted) != 1 || server.deleted[0] != "key-old" {
+		t.Errorf("expected old key to be deleted, got %v", server.deleted)
+	}
+}
+
+func TestProjectApiKeyResourceSelfDelete(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	previousNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = previousNow }()
+
+	server := newFakeApiKeyServer(t)
+	r, s := newTestApiKeyResource(t, server.URL)
+
+	// The test client is configured with the public key "pk"
+	own := testApiKeyModel(t, s, now.AddDate(0, 0, -10))
+	own.PublicKey = types.StringValue("pk")
+
+	destroy := func(state ProjectApiKeyResourceModel) resource.ModifyPlanResponse {
+		req := resource.ModifyPlanRequest{State: stateFromModel(t, s, &state), Plan: destroyPlan(s)}
+		resp := resource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(context.Background(), req, &resp)
+		return resp
+	}
+
+	if resp := destroy(own); !resp.Diagnostics.HasError() {
+		t.Error("expected destroying the provider's own key to fail")
+	}
+	if resp := destroy(testApiKeyModel(t, s, now)); resp.Diagnostics.HasError() {
+		t.Errorf("unexpected error destroying another key: %v", resp.Diagnostics)
+	}
+
+	allowed := own
+	allowed.AllowSelfDelete = types.BoolValue(true)
+	if resp := destroy(allowed); resp.Diagnostics.HasError() {
+		t.Errorf("unexpected error with allow_self_delete: %v", resp.Diagnostics)
+	}
+
+	// Replacing the key deletes it as well
+	replaced := own
+	replaced.Note = types.StringValue("changed")
+	if _, resp := modifyApiKeyPlan(t, r, s, own, replaced); !resp.Diagnostics.HasError() {
+		t.Error("expected replacing the provider's own key to fail")
+	}
+
+	// Rotation deletes the key unless it gets a grace period
+	old := own
+	old.CreatedAt = types.StringValue(now.AddDate(0, 0, -91).Format(time.RFC3339))
+	if _, resp := modifyApiKeyPlan(t, r, s, old, old); !resp.Diagnostics.HasError() {
+		t.Error("expected rotating the provider's own key to fail")
+	}
+
+	old.RotationGraceDays = types.Int64Value(7)
+	if _, resp := modifyApiKeyPlan(t, r, s, old, old); resp.Diagnostics.HasError() {
+		t.Errorf("unexpected error rotating with a grace period: %v", resp.Diagnostics)
+	}
+
+	if len(server.deleted) != 0 {
+		t.Errorf("expected planning not to delete keys, got %v", server.deleted)
+	}
+}
@@ -0,0 +1,408 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParseProjectImportID(t *testing.T) {
+	testCases := []struct {
+		importID string
+		orgID    string
+		name     string
+		byName   bool
+	}{
+		{"cm1abc", "", "", false},
+		{"name:checkout-service", "", "checkout-service", true},
+		{"name:with:colon", "", "with:colon", true},
+		{"org:org-1/name:checkout-service", "org-1", "checkout-service", true},
+		{"org:org-1/checkout-service", "", "", true},
+		{"org:/name:checkout-service", "", "", true},
+		{"name:", "", "", true},
+	}
+
+	for _, tc := range testCases {
+		orgID, name, byName := parseProjectImportID(tc.importID)
+		if orgID != tc.orgID || name != tc.name || byName != tc.byName {
+			t.Errorf("parseProjectImportID(%q) = (%q, %q, %t), want (%q, %q, %t)",
+				tc.importID, orgID, name, byName, tc.orgID, tc.name, tc.byName)
+		}
+	}
+}
+
+func TestProjectResourceImportStateByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"projects":[
+			{"id":"project-1","name":"checkout-service"},
+			{"id":"project-2","name":"duplicate"},
+			{"id":"project-3","name":"duplicate"}
+		]}`)
+	}))
+	defer server.Close()
+
+	r, s := newTestProjectResource(t, server.URL)
+
+	importState := func(importID string) resource.ImportStateResponse {
+		resp := resource.ImportStateResponse{State: emptyState(s)}
+		r.ImportState(context.Background(), resource.ImportStateRequest{ID: importID}, &resp)
+		return resp
+	}
+
+	for importID, expected := range map[string]string{
+		"project-9":                       "project-9",
+		"name:checkout-service":           "project-1",
+		"org:org-1/name:checkout-service": "project-1",
+	} {
+		resp := importState(importID)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected error: %v", importID, resp.Diagnostics)
+		}
+
+		var id types.String
+		resp.State.GetAttribute(context.Background(), tfpath.Root("id"), &id)
+		if id.ValueString() != expected {
+			t.Errorf("%s: expected id %s, got %s", importID, expected, id)
+		}
+	}
+
+	for _, importID := range []string{"name:missing", "name:duplicate", "name:", "org:org-1/duplicate"} {
+		if resp := importState(importID); !resp.Diagnostics.HasError() {
+			t.Errorf("%s: expected an error", importID)
+		}
+	}
+}
+
+func TestProjectResourceDeletionProtection(t *testing.T) {
+	var deletes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deletes++
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		fmt.Fprint(w, `{"projects":[]}`)
+	}))
+	defer server.Close()
+
+	r, s := newTestProjectResource(t, server.URL)
+
+	model := ProjectResourceModel{
+		ID:                 types.StringValue("project-1"),
+		Name:               types.StringValue("checkout-service"),
+		Metadata:           types.MapNull(types.StringType),
+		MetadataAll:        types.MapNull(types.StringType),
+		RetentionDays:      types.Int64Null(),
+		CreatedAt:          types.StringNull(),
+		UpdatedAt:          types.StringNull(),
+		WaitForDeletion:    types.BoolValue(false),
+		DeletionProtection: types.BoolNull(),
+		Timeouts:           nullTimeouts(t, s),
+	}
+
+	// Unset deletion_protection is planned from the default
+	modifyPlan := func(config ProjectResourceModel) types.Bool {
+		plan := planFromModel(t, s, &config)
+		req := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: s, Raw: plan.Raw},
+			State:  stateFromModel(t, s, &model),
+			Plan:   plan,
+		}
+		resp := resource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(context.Background(), req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected error: %v", resp.Diagnostics)
+		}
+
+		var planned ProjectResourceModel
+		resp.Plan.Get(context.Background(), &planned)
+		return planned.DeletionProtection
+	}
+
+	if planned := modifyPlan(model); !planned.Equal(types.BoolValue(true)) {
+		t.Errorf("expected deletion_protection to default to true, got %s", planned)
+	}
+
+	unprotected := false
+	r.client.Defaults.DeletionProtection = &unprotected
+	if planned := modifyPlan(model); !planned.Equal(types.BoolValue(false)) {
+		t.Errorf("expected the provider default to apply, got %s", planned)
+	}
+
+	explicit := model
+	explicit.DeletionProtection = types.BoolValue(true)
+	if planned := modifyPlan(explicit); !planned.Equal(types.BoolValue(true)) {
+		t.Errorf("expected the configured value to win, got %s", planned)
+	}
+
+	// Delete fails while the project is protected
+	deleteProject := func(deletionProtection types.Bool) resource.DeleteResponse {
+		state := model
+		state.DeletionProtection = deletionProtection
+		req := resource.DeleteRequest{State: stateFromModel(t, s, &state)}
+		resp := resource.DeleteResponse{State: req.State}
+		r.Delete(context.Background(), req, &resp)
+		return resp
+	}
+
+	if resp := deleteProject(types.BoolValue(true)); !resp.Diagnostics.HasError() || deletes != 0 {
+		t.Errorf("expected a protected project not to be deleted, got %d deletes and %v", deletes, resp.Diagnostics)
+	}
+	if resp := deleteProject(types.BoolValue(false)); resp.Diagnostics.HasError() || deletes != 1 {
+		t.Errorf("expected an unprotected project to be deleted, got %d deletes and %v", deletes, resp.Diagnostics)
+	}
+}
+
+// namedProjects returns projects with the given names
+func namedProjects(names ...string) []map[string]interface{} {
+	projects := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		projects[i] = map[string]interface{}{
+			"id":        fmt.Sprintf("project-%d", i+1),
+			"name":      name,
+			"createdAt": "2024-01-01T00:00:00.000Z",
+		}
+	}
+
+	return projects
+}
+
+// testProjectModel returns the planned model of a new project
+func testProjectModel(t *testing.T, s schema.Schema, adoptExisting bool) ProjectResourceModel {
+	t.Helper()
+
+	return ProjectResourceModel{
+		ID:                 types.StringUnknown(),
+		Name:               types.StringValue("checkout-service"),
+		Metadata:           types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("payments")}),
+		RetentionDays:      types.Int64Null(),
+		CreatedAt:          types.StringUnknown(),
+		UpdatedAt:          types.StringUnknown(),
+		MetadataJSON:       jsontypes.NewNormalizedUnknown(),
+		MetadataAll:        types.MapUnknown(types.StringType),
+		WaitForDeletion:    types.BoolValue(true),
+		DeletionProtection: types.BoolValue(true),
+		AdoptExisting:      types.BoolValue(adoptExisting),
+		Timeouts:           nullTimeouts(t, s),
+	}
+}
+
+func TestProjectResourceAdoptExisting(t *testing.T) {
+	testCases := map[string]struct {
+		names         []string
+		adoptExisting bool
+		expectError   bool
+		expectID      string
+		created       int
+		updated       int
+	}{
+		"adopts single match":      {names: []string{"other", "checkout-service"}, adoptExisting: true, expectID: "project-2", updated: 1},
+		"creates without match":    {names: []string{"other"}, adoptExisting: true, expectID: "project-new", created: 1},
+		"fails on ambiguous match": {names: []string{"checkout-service", "checkout-service"}, adoptExisting: true, expectError: true},
+		"creates duplicate":        {names: []string{"checkout-service"}, expectID: "project-new", created: 1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := newFakeProjectsServer(t, namedProjects(tc.names...)...)
+			r, s := newTestProjectResource(t, server.URL)
+
+			plan := testProjectModel(t, s, tc.adoptExisting)
+			req := resource.CreateRequest{Plan: planFromModel(t, s, &plan)}
+			resp := resource.CreateResponse{State: emptyState(s)}
+			r.Create(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if len(server.created) != tc.created || len(server.updated) != tc.updated {
+				t.Errorf("expected %d creates and %d updates, got %v and %v", tc.created, tc.updated, server.created, server.updated)
+			}
+			if tc.expectError {
+				return
+			}
+
+			var state ProjectResourceModel
+			resp.State.Get(context.Background(), &state)
+			if state.ID.ValueString() != tc.expectID {
+				t.Errorf("expected project %s, got %s", tc.expectID, state.ID)
+			}
+			if state.CreatedAt.IsUnknown() || state.CreatedAt.ValueString() == "" {
+				t.Errorf("expected created_at to be set, got %s", state.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestProjectResourceDuplicateNameWarning(t *testing.T) {
+	server := newFakeProjectsServer(t, namedProjects("checkout-service")...)
+	r, s := newTestProjectResource(t, server.URL)
+
+	modifyPlan := func(state tfsdk.State, plan ProjectResourceModel) resource.ModifyPlanResponse {
+		p := planFromModel(t, s, &plan)
+		req := resource.ModifyPlanRequest{Config: tfsdk.Config{Schema: s, Raw: p.Raw}, State: state, Plan: p}
+		resp := resource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(context.Background(), req, &resp)
+		return resp
+	}
+
+	for adopt, summary := range map[bool]string{false: "Duplicate Project Name", true: "Existing Project Will Be Adopted"} {
+		resp := modifyPlan(emptyState(s), testProjectModel(t, s, adopt))
+		if resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics.Warnings()[0].Summary() != summary {
+			t.Errorf("adopt_existing=%t: expected a %q warning, got %v", adopt, summary, resp.Diagnostics)
+		}
+	}
+
+	// The existing project itself is not a duplicate
+	existing := testProjectModel(t, s, false)
+	existing.ID = types.StringValue("project-1")
+	existing.Name = types.StringValue("renamed")
+	existing.CreatedAt = types.StringNull()
+	existing.UpdatedAt = types.StringNull()
+
+	renamed := existing
+	renamed.Name = types.StringValue("checkout-service")
+	if resp := modifyPlan(stateFromModel(t, s, &existing), renamed); resp.Diagnostics.WarningsCount() != 0 {
+		t.Errorf("expected no warning when renaming the project back, got %v", resp.Diagnostics)
+	}
+}
+
+// newFakeProjectServer starts a fake server holding a single project
+func newFakeProjectServer(t *testing.T) *fakeProjectsServer {
+	t.Helper()
+
+	return newFakeProjectsServer(t, map[string]interface{}{
+		"id":        "project-1",
+		"name":      "checkout-service",
+		"createdAt": "2024-01-01T00:00:00.000Z",
+		"updatedAt": "2024-01-01T00:00:00.000Z",
+	})
+}
+
+func TestProjectResourceUpdateClearsRemovedValues(t *testing.T) {
+	fake := newFakeProjectServer(t)
+	server := configuredProviderServer(t, fake.URL)
+	_, s := newTestProjectResource(t, fake.URL)
+
+	state := testProjectModel(t, s, false)
+	state.ID = types.StringValue("project-1")
+	state.Metadata = types.MapNull(types.StringType)
+	state.MetadataJSON = jsontypes.NewNormalizedValue(`{}`)
+	state.MetadataAll = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	state.CreatedAt = types.StringValue("2024-01-01T00:00:00.000Z")
+	state.UpdatedAt = types.StringValue("2024-01-01T00:00:00.000Z")
+
+	metadataType := tftypes.Map{ElementType: tftypes.String}
+	str := func(value string) tftypes.Value { return tftypes.NewValue(tftypes.String, value) }
+
+	steps := []struct {
+		name      string
+		metadata  map[string]tftypes.Value
+		retention *int64
+		expected  string
+	}{
+		{"add", map[string]tftypes.Value{"team": str("payments")}, int64Ptr(30), `{"team":"payments"}`},
+		{"change", map[string]tftypes.Value{"team": str("checkout"), "env": str("prod")}, int64Ptr(90), `{"env":"prod","team":"checkout"}`},
+		{"remove", nil, nil, `{}`},
+	}
+
+	prior := stateFromModel(t, s, &state)
+	for _, step := range steps {
+		changes := map[string]tftypes.Value{
+			"metadata":       tftypes.NewValue(metadataType, nil),
+			"metadata_json":  tftypes.NewValue(tftypes.String, nil),
+			"retention_days": tftypes.NewValue(tftypes.Number, nil),
+		}
+		if step.metadata != nil {
+			changes["metadata"] = tftypes.NewValue(metadataType, step.metadata)
+		}
+		if step.retention != nil {
+			changes["retention_days"] = tftypes.NewValue(tftypes.Number, *step.retention)
+		}
+
+		newState := applyResourceChange(t, server, "langfuse_project", s, prior, changes)
+		if !newState.IsFullyKnown() {
+			t.Fatalf("%s: expected the new state to be known, got %s", step.name, newState)
+		}
+
+		var updated ProjectResourceModel
+		prior = tfsdk.State{Schema: s, Raw: newState}
+		if diags := prior.Get(context.Background(), &updated); diags.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", step.name, diags)
+		}
+
+		// The update sends every value, so removed values are reset
+		sent := fake.updates[len(fake.updates)-1]
+		if _, ok := sent["metadata"]; !ok {
+			t.Errorf("%s: expected metadata to be sent, got %v", step.name, sent)
+		}
+		expectedRetention := float64(0)
+		if step.retention != nil {
+			expectedRetention = float64(*step.retention)
+		}
+		if sent["retention"] != expectedRetention {
+			t.Errorf("%s: expected retention %v to be sent, got %v", step.name, expectedRetention, sent["retention"])
+		}
+
+		// The new state matches the configuration and the server
+		if !updated.RetentionDays.Equal(types.Int64PointerValue(step.retention)) || updated.Metadata.IsNull() != (step.metadata == nil) {
+			t.Errorf("%s: state does not match the configuration: %+v", step.name, updated)
+		}
+		if updated.MetadataJSON.ValueString() != step.expected {
+			t.Errorf("%s: expected metadata_json %s, got %s", step.name, step.expected, updated.MetadataJSON)
+		}
+		if updated.CreatedAt.ValueString() != "2024-01-01T00:00:00.000Z" {
+			t.Errorf("%s: expected created_at to be kept, got %s", step.name, updated.CreatedAt)
+		}
+	}
+}
+
+func TestProjectResourceReadReflectsServer(t *testing.T) {
+	server := newFakeProjectServer(t)
+	r, s := newTestProjectResource(t, server.URL)
+
+	state := testProjectModel(t, s, false)
+	state.ID = types.StringValue("project-1")
+	state.RetentionDays = types.Int64Value(30)
+	state.MetadataJSON = jsontypes.NewNormalizedValue(`{"team":"payments"}`)
+	state.CreatedAt = types.StringNull()
+	state.UpdatedAt = types.StringNull()
+
+	// Metadata and retention were removed outside of Terraform
+	server.projects[0]["metadata"] = map[string]interface{}{}
+	server.projects[0]["retentionDays"] = 0
+
+	req := resource.ReadRequest{State: stateFromModel(t, s, &state)}
+	resp := resource.ReadResponse{State: req.State}
+	r.Read(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var refreshed ProjectResourceModel
+	resp.State.Get(context.Background(), &refreshed)
+	if len(refreshed.Metadata.Elements()) != 0 || refreshed.Metadata.IsNull() {
+		t.Errorf("expected an empty metadata map, got %s", refreshed.Metadata)
+	}
+	if refreshed.MetadataJSON.ValueString() != `{}` {
+		t.Errorf("expected empty metadata_json, got %s", refreshed.MetadataJSON)
+	}
+	if refreshed.RetentionDays.ValueInt64() != 0 || refreshed.RetentionDays.IsNull() {
+		t.Errorf("expected retention to be disabled, got %s", refreshed.RetentionDays)
+	}
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// TransportOptions configures TLS and proxying for connections to the
+// Langfuse API
+type TransportOptions struct {
+	// CACertPEM holds PEM encoded CA certificates to trust
+	CACertPEM string
+	// CACertFile is the path to a file with PEM encoded CA certificates to trust
+	CACertFile string
+	// ClientCert holds the PEM encoded client certificate for mutual TLS
+	ClientCert string
+	// ClientKey holds the PEM encoded private key of the client certificate
+	ClientKey string
+	// InsecureSkipVerify disables verification of the server certificate
+	InsecureSkipVerify bool
+	// ProxyURL routes requests through the given proxy instead of the one
+	// selected by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables
+	ProxyURL string
+}
+
+// newTransport builds an HTTP transport from the options. The transport
+// starts from the default transport, so proxy environment variables and
+// connection pooling behave as usual unless overridden.
+func newTransport(opts TransportOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify, //nolint:gosec // explicitly requested by the user
+	}
+
+	if opts.CACertPEM != "" || opts.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if opts.CACertPEM != "" {
+			if !pool.AppendCertsFromPEM([]byte(opts.CACertPEM)) {
+				return nil, fmt.Errorf("no valid PEM encoded certificates found in CA certificate")
+			}
+		}
+
+		if opts.CACertFile != "" {
+			caCert, err := os.ReadFile(opts.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(caCert) {
+				return nil, fmt.Errorf("no valid PEM encoded certificates found in %s", opts.CACertFile)
+			}
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, fmt.Errorf("client certificate and client key must be set together")
+		}
+
+		cert, err := tls.X509KeyPair([]byte(opts.ClientCert), []byte(opts.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := parseProxyURL(opts.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
+}
+
+// parseProxyURL parses and validates a proxy URL
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", rawURL)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", rawURL)
+	}
+
+	return proxyURL, nil
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert holds a generated certificate and its PEM encoding
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// generateTestCert creates a certificate signed by parent, or a self-signed
+// CA certificate when parent is nil.
+func generateTestCert(t *testing.T, commonName string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// newTestTLSServer starts a TLS server with a certificate signed by ca. When
+// clientCA is set, the server requires client certificates signed by it.
+func newTestTLSServer(t *testing.T, ca, clientCA *testCert) *httptest.Server {
+	t.Helper()
+
+	serverCert := generateTestCert(t, "langfuse.test", ca, x509.ExtKeyUsageServerAuth)
+	keyPair, err := tls.X509KeyPair([]byte(serverCert.certPEM), []byte(serverCert.keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"projects":[]}`)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{keyPair}}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		server.TLS.ClientCAs = pool
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func listProjectsWithTLS(t *testing.T, serverURL string, opts TransportOptions) error {
+	t.Helper()
+
+	transport, err := newTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = NewClient(serverURL, "sk", "pk", WithTransport(transport)).ListProjects(context.Background())
+	return err
+}
+
+func TestTransportCustomCA(t *testing.T) {
+	ca := generateTestCert(t, "Test CA", nil, 0)
+	server := newTestTLSServer(t, ca, nil)
+
+	if err := listProjectsWithTLS(t, server.URL, TransportOptions{}); err == nil {
+		t.Error("expected certificate verification to fail without the CA")
+	}
+
+	if err := listProjectsWithTLS(t, server.URL, TransportOptions{CACertPEM: ca.certPEM}); err != nil {
+		t.Errorf("expected request with CA PEM to succeed, got: %s", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(ca.certPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := listProjectsWithTLS(t, server.URL, TransportOptions{CACertFile: caFile}); err != nil {
+		t.Errorf("expected request with CA file to succeed, got: %s", err)
+	}
+
+	if err := listProjectsWithTLS(t, server.URL, TransportOptions{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("expected insecure request to succeed, got: %s", err)
+	}
+}
+
+func TestTransportClientCertificate(t *testing.T) {
+	ca := generateTestCert(t, "Test CA", nil, 0)
+	clientCA := generateTestCert(t, "Test Client CA", nil, 0)
+	clientCert := generateTestCert(t, "terraform", clientCA, x509.ExtKeyUsageClientAuth)
+	server := newTestTLSServer(t, ca, clientCA)
+
+	if err := listProjectsWithTLS(t, server.URL, TransportOptions{CACertPEM: ca.certPEM}); err == nil {
+		t.Error("expected request without client certificate to fail")
+	}
+
+	err := listProjectsWithTLS(t, server.URL, TransportOptions{
+		CACertPEM:  ca.certPEM,
+		ClientCert: clientCert.certPEM,
+		ClientKey:  clientCert.keyPEM,
+	})
+	if err != nil {
+		t.Errorf("expected request with client certificate to succeed, got: %s", err)
+	}
+}
+
+func TestTransportInvalidOptions(t *testing.T) {
+	testCases := map[string]TransportOptions{
+		"invalid CA PEM":     {CACertPEM: "not a certificate"},
+		"missing CA file":    {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"missing client key": {ClientCert: "cert"},
+		"invalid key pair":   {ClientCert: "cert", ClientKey: "key"},
+	}
+
+	for name, opts := range testCases {
+		if _, err := newTransport(opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestTransportProxyURL(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.Host
+		fmt.Fprint(w, `{"projects":[]}`)
+	}))
+	defer proxy.Close()
+
+	transport, err := newTransport(TransportOptions{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewClient("http://langfuse.internal.test", "sk", "pk", WithTransport(transport)).ListProjects(context.Background())
+	if err != nil {
+		t.Fatalf("expected request through proxy to succeed, got: %s", err)
+	}
+	if proxiedHost != "langfuse.internal.test" {
+		t.Errorf("expected request to be proxied for langfuse.internal.test, got %q", proxiedHost)
+	}
+
+	for _, invalid := range []string{"ftp://proxy.test", "http://", "://"} {
+		if _, err := newTransport(TransportOptions{ProxyURL: invalid}); err == nil {
+			t.Errorf("expected error for proxy URL %q", invalid)
+		}
+	}
+}
+
+func TestClientHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		fmt.Fprint(w, `{"projects":[]}`)
+	}))
+	defer server.Close()
+
+	headers := map[string]string{
+		"CF-Access-Client-Id":     "client-id",
+		"CF-Access-Client-Secret": "client-secret",
+	}
+	client := NewClient(server.URL, "sk", "pk", WithHeaders(headers))
+	headers["CF-Access-Client-Id"] = "modified"
+
+	if _, err := client.ListProjects(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := received.Get("CF-Access-Client-Id"); got != "client-id" {
+		t.Errorf("unexpected CF-Access-Client-Id header: %q", got)
+	}
+	if got := received.Get("CF-Access-Client-Secret"); got != "client-secret" {
+		t.Errorf("unexpected CF-Access-Client-Secret header: %q", got)
+	}
+	if user, pass, ok := (&http.Request{Header: received}).BasicAuth(); !ok || user != "pk" || pass != "sk" {
+		t.Errorf("expected basic auth to be preserved, got %q:%q", user, pass)
+	}
+}